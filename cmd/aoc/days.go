@@ -0,0 +1,38 @@
+// The days that have a Go solution, each with its Main function in
+// internal/dayNN (make_day adds new days here)
+
+package main
+
+import (
+	"github.com/andreaskaempf/adventofcode2020/internal/day11"
+	"github.com/andreaskaempf/adventofcode2020/internal/day12"
+	"github.com/andreaskaempf/adventofcode2020/internal/day13"
+	"github.com/andreaskaempf/adventofcode2020/internal/day14"
+	"github.com/andreaskaempf/adventofcode2020/internal/day15"
+	"github.com/andreaskaempf/adventofcode2020/internal/day16"
+	"github.com/andreaskaempf/adventofcode2020/internal/day17"
+	"github.com/andreaskaempf/adventofcode2020/internal/day18"
+	"github.com/andreaskaempf/adventofcode2020/internal/day19"
+	"github.com/andreaskaempf/adventofcode2020/internal/day20"
+	"github.com/andreaskaempf/adventofcode2020/internal/day21"
+	"github.com/andreaskaempf/adventofcode2020/internal/day22"
+	"github.com/andreaskaempf/adventofcode2020/internal/day23"
+	"github.com/andreaskaempf/adventofcode2020/internal/day24"
+)
+
+var days = map[string]func(args []string){
+	"day11": day11.Main,
+	"day12": day12.Main,
+	"day13": day13.Main,
+	"day14": day14.Main,
+	"day15": day15.Main,
+	"day16": day16.Main,
+	"day17": day17.Main,
+	"day18": day18.Main,
+	"day19": day19.Main,
+	"day20": day20.Main,
+	"day21": day21.Main,
+	"day22": day22.Main,
+	"day23": day23.Main,
+	"day24": day24.Main,
+}
@@ -0,0 +1,68 @@
+// Advent of Code 2020: run the Go solution for a day, with any options for
+// that day after the day number, e.g.
+//
+//	go run ./cmd/aoc 24 -max 10
+//
+// Each day runs in its own directory (internal/dayNN, from the top of the
+// repository), so input.txt, sample.txt and files written by options such
+// as -gif are found there, as when each day was a separate program.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+
+	// Command line options, before the day number
+	dir := flag.String("dir", "", "directory to run the day in (default internal/dayNN)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: aoc [-dir directory] <day> [options for the day]")
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "Days:", dayList())
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Find the day, e.g., "7", "07" or "day07"
+	name := flag.Arg(0)
+	if n, err := strconv.Atoi(name); err == nil {
+		name = fmt.Sprintf("day%02d", n)
+	}
+	run, ok := days[name]
+	if !ok {
+		fmt.Printf("No Go solution for %s, try one of: %s\n", flag.Arg(0), dayList())
+		os.Exit(2)
+	}
+
+	// Change to the directory for the day, and run it
+	if *dir == "" {
+		*dir = filepath.Join("internal", name)
+	}
+	if err := os.Chdir(*dir); err != nil {
+		fmt.Println("Error:", err)
+		fmt.Println("Run from the top of the repository, or use -dir")
+		os.Exit(1)
+	}
+	run(flag.Args()[1:])
+}
+
+// Names of the days that can be run, in order
+func dayList() string {
+	names := []string{}
+	for name := range days {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
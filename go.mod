@@ -0,0 +1,3 @@
+module github.com/andreaskaempf/adventofcode2020
+
+go 1.22
@@ -0,0 +1,127 @@
+// Animated GIF export: each frame is rows of characters, the same as the
+// frames shown in the terminal, with each character (e.g., floor, empty
+// seat and occupied seat for day 11) drawn as a block of colour
+
+package anim
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Collects frames, then writes them to an animated GIF
+type GIFWriter struct {
+	chars   string        // characters drawn, in the same order as palette
+	palette color.Palette // background, then colour of each character
+	cell    int           // size of each cell in pixels
+	width   int           // width of each character in cells
+	delay   int           // delay between frames, in 100ths of a second
+	frames  []gifFrame    // frames collected so far
+}
+
+// Rows of characters for one frame, and position of the first character
+// (in characters), so that frames covering different areas line up
+type gifFrame struct {
+	rows      [][]byte
+	left, top int
+}
+
+// Create a writer that draws the given characters in the given colours,
+// which are a comma-separated list of RRGGBB hex values: first the
+// background (used for spaces and anything else), then one for each
+// character
+func NewGIFWriter(chars, colours string, cell, width int, fps float64) (*GIFWriter, error) {
+	g := &GIFWriter{chars: chars, cell: cell, width: width}
+	for _, c := range strings.Split(colours, ",") {
+		n, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(c), "#"), 16, 32)
+		if err != nil || len(strings.TrimPrefix(strings.TrimSpace(c), "#")) != 6 {
+			return nil, fmt.Errorf("invalid colour %q, should be RRGGBB", c)
+		}
+		g.palette = append(g.palette, color.RGBA{uint8(n >> 16), uint8(n >> 8), uint8(n), 255})
+	}
+	if len(g.palette) != len(chars)+1 {
+		return nil, fmt.Errorf("need %d colours (background and %q), got %d",
+			len(chars)+1, chars, len(g.palette))
+	}
+	if cell < 1 {
+		return nil, fmt.Errorf("invalid cell size %d", cell)
+	}
+	if fps <= 0 {
+		fps = 10
+	}
+	g.delay = int(100/fps + 0.5)
+	return g, nil
+}
+
+// Add a frame whose first character is at the given position, copying the
+// rows since the caller may reuse them
+func (g *GIFWriter) Add(rows [][]byte, left, top int) {
+	frame := gifFrame{make([][]byte, len(rows)), left, top}
+	for i, r := range rows {
+		frame.rows[i] = append([]byte{}, r...)
+	}
+	g.frames = append(g.frames, frame)
+}
+
+// Draw all the frames and write the GIF; all frames cover the area of all
+// the frames together, with the rest filled with background
+func (g *GIFWriter) Save(filename string) error {
+	if len(g.frames) == 0 {
+		return fmt.Errorf("no frames to write to %s", filename)
+	}
+
+	// Find area covered by all the frames, in characters
+	var minX, minY, maxX, maxY int
+	for i, f := range g.frames {
+		w := 0
+		for _, r := range f.rows {
+			w = max(w, len(r))
+		}
+		if i == 0 {
+			minX, minY, maxX, maxY = f.left, f.top, f.left+w, f.top+len(f.rows)
+		}
+		minX, minY = min(minX, f.left), min(minY, f.top)
+		maxX, maxY = max(maxX, f.left+w), max(maxY, f.top+len(f.rows))
+	}
+	w := (maxX - minX + g.width - 1) * g.cell // last character may be wider than one cell
+	h := (maxY - minY) * g.cell
+
+	// Draw each frame
+	anim := &gif.GIF{}
+	for _, f := range g.frames {
+		img := image.NewPaletted(image.Rect(0, 0, max(w, 1), max(h, 1)), g.palette)
+		for y, r := range f.rows {
+			y += f.top - minY
+			for x, c := range r {
+				x += f.left - minX
+				i := strings.IndexByte(g.chars, c)
+				if i < 0 {
+					continue // background
+				}
+				for py := y * g.cell; py < (y+1)*g.cell; py++ {
+					for px := x * g.cell; px < (x+g.width)*g.cell; px++ {
+						img.SetColorIndex(px, py, uint8(i+1))
+					}
+				}
+			}
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, g.delay)
+	}
+
+	// Write the file
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,116 @@
+// Unit tests for GIF export
+
+package anim
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"testing"
+)
+
+// Colours must be RRGGBB (with an optional #), one more than the characters,
+// and cells at least one pixel
+func TestNewGIFWriter(t *testing.T) {
+	if _, err := NewGIFWriter(".#", "#000000, 303030,40FF40", 1, 1, 10); err != nil {
+		t.Error("Valid options should not be an error:", err)
+	}
+	for _, tc := range []struct {
+		colours string
+		cell    int
+	}{
+		{"000000,303030", 8},               // too few colours
+		{"000000,303030,40ff40,ffffff", 8}, // too many
+		{"000000,30303,40ff40", 8},         // too short
+		{"000000,3030300,40ff40", 8},       // too long
+		{"000000,gggggg,40ff40", 8},        // not hex
+		{"000000,,40ff40", 8},              // missing
+		{"000000,303030,40ff40", 0},        // no cell
+		{"000000,303030,40ff40", -2},       // negative cell
+	} {
+		if _, err := NewGIFWriter(".#", tc.colours, tc.cell, 1, 10); err == nil {
+			t.Errorf("Colours %q, cell %d should be an error", tc.colours, tc.cell)
+		}
+	}
+}
+
+// Save and decode a GIF
+func saveAndDecode(t *testing.T, g *GIFWriter) *gif.GIF {
+	fname := t.TempDir() + "/anim.gif"
+	if err := g.Save(fname); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return anim
+}
+
+// Frames covering different areas should line up, in one image covering all
+// of them, with each character drawn as a block of its colour
+func TestSave(t *testing.T) {
+	g, err := NewGIFWriter(".#", "000000,303030,40ff40", 2, 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]byte{[]byte("#."), []byte(".#")}
+	g.Add(rows, 0, 0)
+	rows[0][0] = '.' // should not change the frame already added
+	g.Add([][]byte{[]byte("##")}, 1, -1)
+
+	// Characters cover x 0 to 2 and y -1 to 1, so the image is 3x3 cells
+	anim := saveAndDecode(t, g)
+	if len(anim.Image) != 2 || len(anim.Delay) != 2 || anim.Delay[0] != 25 {
+		t.Fatal("Should be 2 frames, 25/100 s apart, not", len(anim.Image), anim.Delay)
+	}
+	for i, img := range anim.Image {
+		if img.Bounds() != image.Rect(0, 0, 6, 6) {
+			t.Error("Frame", i, "should be 6x6, not", img.Bounds())
+		}
+	}
+	if c := anim.Image[0].Palette[2]; c != (color.RGBA{0x40, 0xff, 0x40, 255}) {
+		t.Error("Colour of # should be 40ff40, not", c)
+	}
+
+	// Colour index of each cell (0 background, 1 for ., 2 for #)
+	for i, want := range [][3][3]uint8{
+		{{0, 0, 0}, {2, 1, 0}, {1, 2, 0}},
+		{{0, 2, 2}, {0, 0, 0}, {0, 0, 0}},
+	} {
+		img := anim.Image[i]
+		for cy := 0; cy < 3; cy++ {
+			for cx := 0; cx < 3; cx++ {
+				for _, p := range []image.Point{{0, 0}, {1, 1}} { // corners of the cell
+					x, y := cx*2+p.X, cy*2+p.Y
+					if c := img.ColorIndexAt(x, y); c != want[cy][cx] {
+						t.Errorf("Frame %d pixel %d,%d should be colour %d, not %d", i, x, y, want[cy][cx], c)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Characters two cells wide (as for day 24's hexagons) should fit in the
+// image, and saving nothing should be an error
+func TestSaveWide(t *testing.T) {
+	g, _ := NewGIFWriter(".#", "000000,303030,40ff40", 3, 2, 10)
+	if err := g.Save(t.TempDir() + "/empty.gif"); err == nil {
+		t.Error("Saving no frames should be an error")
+	}
+	g.Add([][]byte{[]byte("# #")}, 0, 0)
+	anim := saveAndDecode(t, g)
+	if b := anim.Image[0].Bounds(); b != image.Rect(0, 0, 12, 3) {
+		t.Error("Frame should be 12x3, not", b)
+	}
+	if c := anim.Image[0].ColorIndexAt(11, 2); c != 2 {
+		t.Error("Last character should be drawn two cells wide, not", c)
+	}
+}
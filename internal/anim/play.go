@@ -0,0 +1,166 @@
+// Package anim shows the simulations of days 11, 17 and 24 as they run.
+//
+// Animated playback in the terminal: each frame is redrawn in place using
+// ANSI escape codes. When stdin is a terminal, the space bar pauses and
+// resumes, n steps one frame while paused, and q quits. Ctrl-C (or SIGTERM)
+// restores the terminal before exiting.
+package anim
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ANSI escape codes
+const (
+	ansiClear     = "\x1b[2J"   // clear screen
+	ansiHome      = "\x1b[H"    // move cursor to top left
+	ansiClearLine = "\x1b[K"    // clear to end of line
+	ansiClearDown = "\x1b[J"    // clear to end of screen
+	ansiHideCur   = "\x1b[?25l" // hide cursor
+	ansiShowCur   = "\x1b[?25h" // show cursor
+)
+
+// Draws frames at a fixed rate, responding to keys if interactive
+type Player struct {
+	delay   time.Duration // time between frames
+	keys    chan byte     // key presses, nil if stdin is not a terminal
+	cbreak  bool          // whether the terminal is in cbreak mode
+	paused  bool
+	quit    bool           // whether the user quit
+	signals chan os.Signal // interrupt and terminate signals
+	mu      sync.Mutex     // held while drawing or closing
+	started bool           // whether screen has been cleared
+	closed  bool
+}
+
+// Create a player showing the given number of frames per second
+func NewPlayer(fps float64) *Player {
+	if fps <= 0 {
+		fps = 10
+	}
+	p := &Player{delay: time.Duration(float64(time.Second) / fps)}
+
+	// If stdin is a terminal, read single key presses without waiting for
+	// Enter, by putting the terminal into cbreak mode until closed
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		if stty("cbreak", "-echo") == nil {
+			p.cbreak = true
+			p.keys = make(chan byte)
+			go func() {
+				buf := make([]byte, 1)
+				for {
+					if n, err := os.Stdin.Read(buf); err != nil || n == 0 {
+						close(p.keys)
+						return
+					}
+					p.keys <- buf[0]
+				}
+			}()
+		}
+	}
+
+	// Restore the terminal if interrupted, since otherwise Ctrl-C would
+	// leave the shell with no echo and no cursor
+	p.signals = make(chan os.Signal, 1)
+	signal.Notify(p.signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if sig, ok := <-p.signals; ok {
+			p.Close()
+			code := 1
+			if s, ok := sig.(syscall.Signal); ok {
+				code = 128 + int(s) // as the shell reports a process killed by a signal
+			}
+			os.Exit(code)
+		}
+	}()
+	return p
+}
+
+// Draw a frame with a title line, then wait for the next frame (or for a key
+// while paused); returns false if the user quit
+func (p *Player) Frame(title string, rows [][]byte) bool {
+	if p.quit {
+		return false
+	}
+
+	// Clear the screen the first time, and afterwards just go back to the
+	// top and draw over the previous frame
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return false
+	}
+	if !p.started {
+		fmt.Print(ansiClear, ansiHideCur)
+		p.started = true
+	}
+	fmt.Print(ansiHome, title, ansiClearLine, "\n")
+	for _, r := range rows {
+		fmt.Print(string(r), ansiClearLine, "\n")
+	}
+	if p.keys != nil {
+		fmt.Print("[space] pause/resume  [n] step  [q] quit", ansiClearLine, "\n")
+	}
+	fmt.Print(ansiClearDown)
+	p.mu.Unlock()
+
+	// Wait for the next frame: while paused, only a key moves on
+	for {
+		var timeout <-chan time.Time
+		if !p.paused {
+			timeout = time.After(p.delay)
+		}
+		select {
+		case <-timeout:
+			return true
+		case k, ok := <-p.keys:
+			if !ok { // stdin closed, just play on
+				p.keys = nil
+				p.paused = false
+				continue
+			}
+			switch k {
+			case 'q', 'Q':
+				p.quit = true
+				return false
+			case ' ':
+				p.paused = !p.paused
+			case 'n', 'N':
+				if p.paused {
+					return true
+				}
+			}
+		}
+	}
+}
+
+// Restore the terminal, and stop handling signals
+func (p *Player) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	signal.Stop(p.signals)
+	close(p.signals)
+	if p.started {
+		fmt.Print(ansiShowCur)
+	}
+	if p.cbreak {
+		stty("-cbreak", "echo")
+	}
+}
+
+// Change terminal settings
+func stty(args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,96 @@
+// Unit tests for terminal playback, with stdin not a terminal
+
+package anim
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/testutil"
+)
+
+// Create a player with stdin a pipe rather than a terminal, so it doesn't
+// read keys or change terminal settings
+func newTestPlayer(t *testing.T, fps float64) *Player {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+	t.Cleanup(func() { r.Close(); w.Close() })
+	p := NewPlayer(fps)
+	if p.keys != nil || p.cbreak {
+		t.Fatal("Player should not read keys when stdin is not a terminal")
+	}
+	return p
+}
+
+// Frames should be drawn over each other, waiting the delay after each, and
+// closing (even twice) should show the cursor once and stop any more frames
+func TestPlayerFrames(t *testing.T) {
+	p := newTestPlayer(t, 20)
+	var ok1, ok2, ok3 bool
+	var elapsed time.Duration
+	out := testutil.Capture(func() {
+		start := time.Now()
+		ok1 = p.Frame("Frame 1", [][]byte{[]byte("#."), []byte(".#")})
+		ok2 = p.Frame("Frame 2", [][]byte{[]byte("..")})
+		elapsed = time.Since(start)
+		p.Close()
+		p.Close()
+		ok3 = p.Frame("Frame 3", nil)
+	})
+	if !ok1 || !ok2 || ok3 {
+		t.Error("Frames before closing should continue, and after should not:", ok1, ok2, ok3)
+	}
+	if elapsed < 2*p.delay {
+		t.Error("Two frames at 20 fps should take at least 100ms, not", elapsed)
+	}
+	want := ansiClear + ansiHideCur +
+		ansiHome + "Frame 1" + ansiClearLine + "\n#." + ansiClearLine + "\n.#" + ansiClearLine + "\n" + ansiClearDown +
+		ansiHome + "Frame 2" + ansiClearLine + "\n.." + ansiClearLine + "\n" + ansiClearDown +
+		ansiShowCur
+	if out != want {
+		t.Errorf("Output should be %q, not %q", want, out)
+	}
+	if strings.Contains(out, "[q] quit") {
+		t.Error("Keys should not be shown when stdin is not a terminal")
+	}
+}
+
+// Keys should pause, step and quit, and after quitting no more frames are
+// drawn
+func TestPlayerKeys(t *testing.T) {
+	p := newTestPlayer(t, 1000)
+	keys := make(chan byte)
+	p.keys = keys
+	go func() {
+		keys <- ' ' // pause, so only a key moves on
+		time.Sleep(50 * time.Millisecond)
+		keys <- 'n' // step one frame
+		keys <- 'q' // quit while still paused
+	}()
+	var ok1, ok2, ok3 bool
+	var elapsed time.Duration
+	out := testutil.Capture(func() {
+		start := time.Now()
+		ok1 = p.Frame("Frame 1", nil)
+		elapsed = time.Since(start)
+		ok2 = p.Frame("Frame 2", nil)
+		ok3 = p.Frame("Frame 3", nil)
+		p.Close()
+	})
+	if !ok1 || ok2 || ok3 || !p.quit {
+		t.Error("Should step one frame, then quit:", ok1, ok2, ok3)
+	}
+	if elapsed < 50*time.Millisecond {
+		t.Error("Frame should wait while paused, not return after", elapsed)
+	}
+	if strings.Contains(out, "Frame 3") || !strings.Contains(out, "[space] pause/resume") {
+		t.Errorf("Should draw two frames with the keys shown:\n%q", out)
+	}
+}
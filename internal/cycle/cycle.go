@@ -0,0 +1,64 @@
+// Package cycle detects when a simulation repeats itself: the state after
+// each generation is encoded in a canonical form, and as soon as a state
+// has been seen before, the simulation can only go round the same loop from
+// then on. This stops rule sets that oscillate from running forever (days
+// 11, 17 and 24). States are looked up by hash, but kept so that states
+// with the same hash are compared in full, and a hash collision can't
+// report a false cycle.
+package cycle
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+)
+
+// How a simulation ended: the state at Generation was seen again Period
+// generations later (Period 1 is a fixed point), or Period is 0 if the
+// iteration cap was reached first
+type Outcome struct {
+	Generation int
+	Period     int
+}
+
+// Describe the outcome
+func (o Outcome) String() string {
+	switch o.Period {
+	case 0:
+		return fmt.Sprintf("no repeat after %d generations", o.Generation)
+	case 1:
+		return fmt.Sprintf("stabilised at generation %d", o.Generation)
+	}
+	return fmt.Sprintf("entered cycle of length %d at generation %d", o.Period, o.Generation)
+}
+
+// Remembers each state seen so far, by its hash
+type Detector struct {
+	seen map[uint64][]seenState
+}
+
+// A state, and the generation at which it was first seen
+type seenState struct {
+	state      []byte
+	generation int
+}
+
+func NewDetector() *Detector {
+	return &Detector{seen: map[uint64][]seenState{}}
+}
+
+// Record the state of a generation, and return the outcome and true if the
+// same state was seen before. The state must be encoded so that equal
+// states have equal bytes, and is kept, so must not be changed afterwards.
+func (d *Detector) Check(state []byte, gen int) (Outcome, bool) {
+	h := fnv.New64a()
+	h.Write(state)
+	key := h.Sum64()
+	for _, s := range d.seen[key] {
+		if bytes.Equal(s.state, state) {
+			return Outcome{s.generation, gen - s.generation}, true
+		}
+	}
+	d.seen[key] = append(d.seen[key], seenState{state, gen})
+	return Outcome{}, false
+}
@@ -0,0 +1,39 @@
+// Unit tests for cycle detection
+
+package cycle
+
+import (
+	"hash/fnv"
+	"testing"
+)
+
+// A repeated state should be found at the generation it was first seen
+func TestCheck(t *testing.T) {
+	d := NewDetector()
+	for gen, s := range []string{"a", "b", "c", "b"} {
+		o, ok := d.Check([]byte(s), gen)
+		if ok != (gen == 3) {
+			t.Fatal("Generation", gen, "repeat should be", gen == 3)
+		}
+		if ok && o != (Outcome{1, 2}) {
+			t.Error("Should be cycle of length 2 at generation 1, not", o)
+		}
+	}
+}
+
+// A different state with the same hash is not a repeat
+func TestCheckCollision(t *testing.T) {
+	d := NewDetector()
+	d.Check([]byte("a"), 0)
+
+	// Plant another state under the hash of "b"
+	h := fnv.New64a()
+	h.Write([]byte("b"))
+	d.seen[h.Sum64()] = []seenState{{[]byte("x"), 1}}
+	if o, ok := d.Check([]byte("b"), 2); ok {
+		t.Error("Hash collision reported as", o)
+	}
+	if o, ok := d.Check([]byte("b"), 3); !ok || o != (Outcome{2, 1}) {
+		t.Error("Should have stabilised at generation 2, not", o, ok)
+	}
+}
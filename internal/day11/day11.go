@@ -0,0 +1,329 @@
+// Advent of Code 2020, Day 11
+//
+// Change the state of a "seating plan" depending on available seats
+// immediately adjacent to every seat (Part 1), or visible in any direction
+// (Part 2).
+//
+// The rules for which seats count as neighbours, and how many occupied
+// neighbours a seat will tolerate, are chosen at run time (see rules.go), so
+// both parts (and other variations) come from one run.
+//
+// AK, 14/01/2022
+
+package day11
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/anim"
+	"github.com/andreaskaempf/adventofcode2020/internal/cycle"
+	"github.com/andreaskaempf/adventofcode2020/internal/parallel"
+)
+
+func Main(args []string) {
+
+	// Command line options
+	fs := flag.NewFlagSet("day11", flag.ExitOnError)
+	fname := fs.String("input", "input.txt", "input file")
+	rules := fs.String("rules", "adjacent,visible",
+		"comma-separated seat rules to run, each name[:tolerance], e.g. vonneumann:2 or visible3")
+	verbose := fs.Bool("v", false, "print the board after each iteration")
+	maxIters := fs.Int("max", 0, "stop after this many iterations (0 for no limit)")
+	workers := fs.Int("workers", 1, "number of goroutines to share the work of each iteration")
+	play := fs.Bool("play", false, "animate the board in the terminal")
+	fps := fs.Float64("fps", 10, "frames per second when animating")
+	gifFile := fs.String("gif", "", "write each iteration to an animated GIF (one per rule if several)")
+	cell := fs.Int("cell", 4, "size of each seat in the GIF, in pixels")
+	colours := fs.String("colours", "000000,c0c0c0,4060ff,ff4040",
+		"GIF colours as RRGGBB: background, floor, empty seat, occupied seat")
+	fs.Parse(args)
+
+	// Check GIF options before doing any work
+	newGIF := func() (*anim.GIFWriter, error) {
+		return anim.NewGIFWriter(".L#", *colours, *cell, 1, *fps)
+	}
+	if *gifFile != "" {
+		if _, err := newGIF(); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+
+	// Parse the rules before doing any work
+	seatRules := []SeatRule{}
+	for _, spec := range strings.Split(*rules, ",") {
+		r, err := parseRule(spec)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		seatRules = append(seatRules, r)
+	}
+
+	// Read each line of input file
+	lines := [][]byte{}
+	f, err := os.Open(*fname)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		t := scanner.Text()
+		l := []byte{}
+		for i := 0; i < len(t); i++ {
+			l = append(l, t[i])
+		}
+		lines = append(lines, l)
+	}
+	f.Close()
+
+	// Choose how to show each iteration, if at all
+	observers := []Observer{}
+	var player *anim.Player
+	if *verbose {
+		observers = append(observers, printIteration)
+	}
+	if *play {
+		player = anim.NewPlayer(*fps)
+		defer player.Close()
+		observers = append(observers, func(name string, iter int, lines [][]byte) bool {
+			title := fmt.Sprintf("%s, iteration %d: %d seats occupied",
+				name, iter, occupied(lines))
+			return player.Frame(title, lines)
+		})
+	}
+
+	// Run the simulation with each set of rules, e.g., Part 1 and Part 2
+	results := []string{}
+	for _, r := range seatRules {
+
+		// Collect frames for a GIF, if required
+		obs := observers
+		var gw *anim.GIFWriter
+		if *gifFile != "" {
+			gw, _ = newGIF()
+			obs = append(obs, func(name string, iter int, lines [][]byte) bool {
+				gw.Add(lines, 0, 0)
+				return true
+			})
+		}
+
+		// Run the simulation
+		var final [][]byte
+		var outcome cycle.Outcome
+		if gr, ok := r.(GraphRule); ok {
+			final, outcome = simulateGraph(lines, gr, *maxIters, *workers, combine(obs))
+		} else {
+			final, outcome = simulate(lines, r, *maxIters, *workers, combine(obs))
+		}
+		results = append(results, fmt.Sprintf("%s: %d seats occupied, %s",
+			r.Name(), occupied(final), outcome))
+
+		// Write the GIF, named after the rule if there are several
+		if gw != nil {
+			fname := *gifFile
+			if len(seatRules) > 1 {
+				fname = strings.TrimSuffix(fname, ".gif") + "_" +
+					strings.ReplaceAll(r.Name(), ":", "_") + ".gif"
+			}
+			if err := gw.Save(fname); err != nil {
+				results = append(results, fmt.Sprint("Error: ", err))
+			}
+		}
+	}
+
+	// Report results after any animation has finished
+	if player != nil {
+		player.Close()
+	}
+	for _, res := range results {
+		fmt.Println(res)
+	}
+}
+
+// Called with the state of the board before the first iteration (iteration
+// 0) and after each iteration; returns false to stop the simulation
+type Observer func(name string, iter int, lines [][]byte) bool
+
+// Combine observers into one, which stops the simulation if any of them
+// does, or nil if there are none
+func combine(observers []Observer) Observer {
+	if len(observers) == 0 {
+		return nil
+	}
+	return func(name string, iter int, lines [][]byte) bool {
+		for _, o := range observers {
+			if !o(name, iter, lines) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Observer that prints the board after each iteration
+func printIteration(name string, iter int, lines [][]byte) bool {
+	if iter == 0 {
+		fmt.Printf("\n%s, starting:\n", name)
+	} else {
+		fmt.Printf("\n%s, iteration %d:\n", name, iter)
+	}
+	printBoard(lines)
+	return true
+}
+
+// Iterate until the board stops changing or repeats an earlier state, using
+// the given rule, or until maxIters iterations (0 for no limit), with the
+// work of each iteration shared between the given number of goroutines,
+// showing each iteration if show is not nil; return the final state of the
+// board and how the simulation ended
+func simulate(lines [][]byte, rule SeatRule, maxIters, workers int, show Observer) ([][]byte, cycle.Outcome) {
+	if show != nil && !show(rule.Name(), 0, lines) {
+		return lines, cycle.Outcome{}
+	}
+	cycles := cycle.NewDetector()
+	cycles.Check(boardState(lines), 0)
+	for iter := 1; maxIters == 0 || iter <= maxIters; iter++ {
+		lines, _ = step(lines, rule, workers)
+
+		// Show result of this iteration
+		if show != nil && !show(rule.Name(), iter, lines) {
+			return lines, cycle.Outcome{Generation: iter}
+		}
+
+		// Stop if this state has been seen before
+		if outcome, ok := cycles.Check(boardState(lines), iter); ok {
+			return lines, outcome
+		}
+	}
+	return lines, cycle.Outcome{Generation: maxIters}
+}
+
+// Apply the rules to every seat simultaneously, returning the new state of
+// the board, and whether anything changed. The rows are shared between the
+// given number of goroutines (see internal/parallel).
+func step(lines [][]byte, rule SeatRule, workers int) ([][]byte, bool) {
+
+	// Make a copy: always look at the current state, but make changes
+	// to a copy, so the changes can be "simulataneous"
+	lines1 := [][]byte{}
+	for _, l := range lines {
+		l1 := make([]byte, len(l), len(l))
+		copy(l1, l)
+		lines1 = append(lines1, l1)
+	}
+
+	// The following rules are applied to every seat simultaneously:
+	// 1. If a seat is empty (L) and there are no occupied seats adjacent
+	//    to it, the seat becomes occupied.
+	// 2. If a seat is occupied (#) and "tolerance" or more seats adjacent
+	//    to it are also occupied, the seat becomes empty (4 for Part 1,
+	//    5 for Part 2).
+	// Otherwise, the seat's state does not change.
+	thresh := rule.Tolerance()
+	changed := make([]bool, max(workers, 1)) // whether each band changed
+	parallel.Bands(len(lines), workers, func(band, lo, hi int) {
+		for r := lo; r < hi; r++ {
+			for c := 0; c < len(lines[r]); c++ {
+				if lines[r][c] == '.' {
+					continue
+				}
+				nOccup := rule.Neighbours(lines, r, c)
+				if lines[r][c] == 'L' && nOccup == 0 {
+					lines1[r][c] = '#'
+					changed[band] = true
+				}
+				if lines[r][c] == '#' && nOccup >= thresh {
+					lines1[r][c] = 'L'
+					changed[band] = true
+				}
+			}
+		}
+	})
+	return lines1, slices.Contains(changed, true)
+}
+
+// Part 1: count the  number of adjacent seats around a given seat that are
+// occupied, just immediate adjacenies up/down/left/right/diagonal
+func adjacentOccupied1(lines [][]byte, r int, c int) int {
+	result := 0
+	for ri := r - 1; ri <= r+1; ri++ {
+		for ci := c - 1; ci <= c+1; ci++ {
+			if ri == r && ci == c {
+				continue
+			}
+			if ri >= 0 && ri < len(lines) && ci >= 0 && ci < len(lines[ri]) && lines[ri][ci] == '#' {
+				result += 1
+			}
+		}
+	}
+	return result
+}
+
+// Part 2: count the  number of visible seats around a given seat that are
+// occupied, in any direction up/down/left/right/diagonal
+func adjacentOccupied2(lines [][]byte, r int, c int) int {
+	return visibleOccupied(lines, r, c, 0)
+}
+
+// Count the number of occupied seats visible from a given seat in any
+// direction, looking at most maxDist places away (0 for no limit)
+func visibleOccupied(lines [][]byte, r, c, maxDist int) int {
+	result := 0
+	diffs := []int{-1, 0, 1}
+	for _, dr := range diffs {
+		for _, dc := range diffs {
+			if !(dr == 0 && dc == 0) && look(lines, r, c, dr, dc, maxDist) == '#' {
+				result++
+			}
+		}
+	}
+	return result
+}
+
+// From a given coordinate, look in any direction, expressed as +/- dx
+// and dy, so dx = 1 and dy = 1 to look diagonally downward, or dx = -1 and
+// dy = 0 to look left, at most maxDist places away (0 for no limit).
+// Returns the first non '.' found, or '.' if that's all that can be seen.
+func look(lines [][]byte, r, c, dr, dc, maxDist int) byte {
+	ri := r + dr
+	ci := c + dc
+	for dist := 1; ri >= 0 && ri < len(lines) && ci >= 0 && ci < len(lines[ri]); dist++ {
+		if maxDist > 0 && dist > maxDist {
+			break
+		}
+		if lines[ri][ci] != '.' {
+			return lines[ri][ci]
+		}
+		ri += dr
+		ci += dc
+	}
+	return '.'
+}
+
+// Count the  number of seats on a board that are occupied
+func occupied(lines [][]byte) int {
+	result := 0
+	for ri := 0; ri < len(lines); ri++ {
+		for ci := 0; ci < len(lines[ri]); ci++ {
+			if lines[ri][ci] == '#' {
+				result += 1
+			}
+		}
+	}
+	return result
+}
+
+// Print a graphic representation of the board
+func printBoard(lines [][]byte) {
+	for _, l := range lines {
+		fmt.Println(string(l))
+	}
+	fmt.Printf("%d seats occupied\n", occupied(lines))
+}
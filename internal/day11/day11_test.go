@@ -0,0 +1,109 @@
+// These are unit tests and benchmarks for Day 11
+
+package day11
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// Read board from a file
+func readBoard(filename string) [][]byte {
+	lines := [][]byte{}
+	f, _ := os.Open(filename)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, []byte(scanner.Text()))
+	}
+	return lines
+}
+
+// Graph simulation should give the same result as the grid simulation, for
+// Part 2 and a distance-limited variant, on sample and puzzle input
+func TestGraphMatchesGrid(t *testing.T) {
+	for _, fname := range []string{"sample.txt", "input.txt"} {
+		lines := readBoard(fname)
+		for _, rule := range []VisibleRule{{0, 5}, {2, 5}} {
+			sb, sbOutcome := simulate(lines, rule, 0, 1, nil)
+			res, resOutcome := simulateGraph(lines, rule, 0, 1, nil)
+			if occupied(res) != occupied(sb) || resOutcome != sbOutcome {
+				t.Error("Graph simulation does not match grid")
+				fmt.Println("Input =", fname, "rule =", rule.Name())
+				fmt.Printf("Expected %d, %s, got %d, %s\n",
+					occupied(sb), sbOutcome, occupied(res), resOutcome)
+			}
+		}
+	}
+}
+
+// Part 2 on the grid, looking in eight directions each time
+func BenchmarkPart2Grid(b *testing.B) {
+	lines := readBoard("input.txt")
+	for i := 0; i < b.N; i++ {
+		simulate(lines, VisibleRule{0, 5}, 0, 1, nil)
+	}
+}
+
+// Part 1, as the program runs it
+func BenchmarkPart1(b *testing.B) {
+	lines := readBoard("input.txt")
+	for i := 0; i < b.N; i++ {
+		simulate(lines, AdjacentRule{4}, 0, 1, nil)
+	}
+}
+
+// Part 2 on the precomputed graph, as the program runs it
+func BenchmarkPart2(b *testing.B) {
+	lines := readBoard("input.txt")
+	for i := 0; i < b.N; i++ {
+		simulateGraph(lines, VisibleRule{0, 5}, 0, 1, nil)
+	}
+}
+
+// Rules that make the sample oscillate should be detected as a cycle, on both
+// the grid and the graph
+func TestCycleDetection(t *testing.T) {
+	lines := readBoard("sample.txt")
+	_, outcome := simulate(lines, AdjacentRule{3}, 0, 1, nil)
+	_, outcome2 := simulateGraph(lines, VisibleRule{1, 3}, 0, 1, nil)
+	if outcome.Period < 2 || outcome != outcome2 {
+		t.Error("Cycle not detected")
+		fmt.Println("Got", outcome, "and", outcome2)
+	}
+}
+
+// Sharing the work between goroutines should give exactly the same boards
+// as one goroutine, for every rule, on the grid and the graph. Run with
+// go test -race to check the workers don't share anything they shouldn't.
+func TestWorkersMatchSequential(t *testing.T) {
+	lines := readBoard("input.txt")
+	for _, rule := range []SeatRule{AdjacentRule{4}, VisibleRule{0, 5}, VonNeumannRule{2}} {
+		sb, sbOutcome := simulate(lines, rule, 0, 1, nil)
+		for _, workers := range []int{2, 3, 8, 1000} {
+			res, resOutcome := simulate(lines, rule, 0, workers, nil)
+			if !bytes.Equal(bytes.Join(res, nil), bytes.Join(sb, nil)) || resOutcome != sbOutcome {
+				t.Error("Grid simulation with workers does not match one worker")
+				fmt.Println("Rule =", rule.Name(), "workers =", workers)
+			}
+			if gr, ok := rule.(GraphRule); ok {
+				res, resOutcome = simulateGraph(lines, gr, 0, workers, nil)
+				if !bytes.Equal(bytes.Join(res, nil), bytes.Join(sb, nil)) || resOutcome != sbOutcome {
+					t.Error("Graph simulation with workers does not match one worker")
+					fmt.Println("Rule =", rule.Name(), "workers =", workers)
+				}
+			}
+		}
+	}
+}
+
+// Part 2 on the grid, with four workers
+func BenchmarkPart2GridWorkers(b *testing.B) {
+	lines := readBoard("input.txt")
+	for i := 0; i < b.N; i++ {
+		simulate(lines, VisibleRule{0, 5}, 0, 4, nil)
+	}
+}
@@ -0,0 +1,162 @@
+// Precomputed neighbour graph for day 11: since seats never move, the seats
+// each seat can see are found once, rather than walking the grid in eight
+// directions for every seat on every iteration. The simulation then runs
+// over this list of seats, with two state arrays that swap roles each
+// iteration instead of copying the board.
+
+package day11
+
+import (
+	"slices"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/cycle"
+	"github.com/andreaskaempf/adventofcode2020/internal/parallel"
+)
+
+// Seats in the plan (floor excluded), and the neighbours of each
+type SeatGraph struct {
+	Seats      [][2]int  // row and column of each seat
+	Neighbours [][]int32 // indices of the neighbouring seats of each seat
+}
+
+// A rule whose neighbours can be precomputed as a graph
+type GraphRule interface {
+	SeatRule
+	Graph(lines [][]byte) *SeatGraph
+}
+
+// Line of sight neighbours for Part 2 (and its distance-limited variants)
+func (v VisibleRule) Graph(lines [][]byte) *SeatGraph {
+	return visibilityGraph(lines, v.Range)
+}
+
+// Build the graph of seats, where the neighbours of a seat are the first
+// seats visible in each of the eight directions, looking at most maxDist
+// places away (0 for no limit)
+func visibilityGraph(lines [][]byte, maxDist int) *SeatGraph {
+
+	// Number each seat, -1 for floor
+	g := &SeatGraph{}
+	index := make([][]int32, len(lines))
+	for r, l := range lines {
+		index[r] = make([]int32, len(l))
+		for c := range l {
+			index[r][c] = -1
+			if l[c] != '.' {
+				index[r][c] = int32(len(g.Seats))
+				g.Seats = append(g.Seats, [2]int{r, c})
+			}
+		}
+	}
+
+	// Look in each direction from each seat, stopping at the first seat
+	diffs := []int{-1, 0, 1}
+	g.Neighbours = make([][]int32, len(g.Seats))
+	for i, s := range g.Seats {
+		for _, dr := range diffs {
+			for _, dc := range diffs {
+				if dr == 0 && dc == 0 {
+					continue
+				}
+				ri, ci := s[0]+dr, s[1]+dc
+				for dist := 1; ri >= 0 && ri < len(lines) && ci >= 0 && ci < len(lines[ri]); dist++ {
+					if maxDist > 0 && dist > maxDist {
+						break
+					}
+					if index[ri][ci] >= 0 {
+						g.Neighbours[i] = append(g.Neighbours[i], index[ri][ci])
+						break
+					}
+					ri += dr
+					ci += dc
+				}
+			}
+		}
+	}
+	return g
+}
+
+// Same as simulate(), but running over the precomputed graph of seats
+func simulateGraph(lines [][]byte, rule GraphRule, maxIters, workers int, show Observer) ([][]byte, cycle.Outcome) {
+	if show != nil && !show(rule.Name(), 0, lines) {
+		return lines, cycle.Outcome{}
+	}
+
+	// Current and next state of each seat, true if occupied
+	g := rule.Graph(lines)
+	cur := make([]bool, len(g.Seats))
+	next := make([]bool, len(g.Seats))
+	for i, s := range g.Seats {
+		cur[i] = lines[s[0]][s[1]] == '#'
+	}
+
+	// Iterate until the state repeats, or the iteration cap is reached
+	thresh := rule.Tolerance()
+	cycles := cycle.NewDetector()
+	cycles.Check(seatsState(cur), 0)
+	for iter := 1; maxIters == 0 || iter <= maxIters; iter++ {
+		changed := stepGraph(g, cur, next, thresh, workers)
+		cur, next = next, cur
+
+		// Show result of this iteration
+		if show != nil && !show(rule.Name(), iter, graphToLines(lines, g, cur)) {
+			return graphToLines(lines, g, cur), cycle.Outcome{Generation: iter}
+		}
+
+		// Stop if no more changes (a fixed point, without needing to hash),
+		// or if this state has been seen before
+		if !changed {
+			return graphToLines(lines, g, cur), cycle.Outcome{Generation: iter - 1, Period: 1}
+		}
+		if outcome, ok := cycles.Check(seatsState(cur), iter); ok {
+			return graphToLines(lines, g, cur), outcome
+		}
+	}
+	return graphToLines(lines, g, cur), cycle.Outcome{Generation: maxIters}
+}
+
+// Apply the rules to every seat, reading the current state and writing the
+// next, and return whether anything changed: an empty seat with no occupied
+// neighbours becomes occupied, an occupied seat with at least thresh
+// occupied neighbours becomes empty. The seats are shared between the given
+// number of goroutines (see internal/parallel).
+func stepGraph(g *SeatGraph, cur, next []bool, thresh, workers int) bool {
+	changed := make([]bool, max(workers, 1)) // whether each band changed
+	parallel.Bands(len(g.Neighbours), workers, func(band, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			nOccup := 0
+			for _, j := range g.Neighbours[i] {
+				if cur[j] {
+					nOccup++
+				}
+			}
+			next[i] = cur[i]
+			if !cur[i] && nOccup == 0 {
+				next[i] = true
+				changed[band] = true
+			} else if cur[i] && nOccup >= thresh {
+				next[i] = false
+				changed[band] = true
+			}
+		}
+	})
+	return slices.Contains(changed, true)
+}
+
+// Convert the state of each seat back into a board, for printing
+func graphToLines(lines [][]byte, g *SeatGraph, state []bool) [][]byte {
+	lines1 := [][]byte{}
+	for _, l := range lines {
+		l1 := make([]byte, len(l), len(l))
+		copy(l1, l)
+		lines1 = append(lines1, l1)
+	}
+	for i, s := range g.Seats {
+		if state[i] {
+			lines1[s[0]][s[1]] = '#'
+		} else {
+			lines1[s[0]][s[1]] = 'L'
+		}
+	}
+	return lines1
+}
@@ -0,0 +1,138 @@
+// Seat rules for day 11: which seats count as neighbours of a seat, and how
+// many occupied neighbours an occupied seat will tolerate before it becomes
+// empty
+
+package day11
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A rule for the seating simulation
+type SeatRule interface {
+	Name() string                            // description of rule
+	Neighbours(lines [][]byte, r, c int) int // number of occupied neighbours
+	Tolerance() int                          // seat empties at this many
+}
+
+// Part 1: the eight immediately adjacent seats
+type AdjacentRule struct {
+	Limit int
+}
+
+// Rule as given to -rules, e.g., adjacent:4
+func (a AdjacentRule) Name() string {
+	return fmt.Sprintf("adjacent:%d", a.Limit)
+}
+
+// Number of the eight adjacent seats that are occupied
+func (a AdjacentRule) Neighbours(lines [][]byte, r, c int) int {
+	return adjacentOccupied1(lines, r, c)
+}
+
+// Occupied neighbours at which an occupied seat empties
+func (a AdjacentRule) Tolerance() int {
+	return a.Limit
+}
+
+// Part 2: the first seat visible in each of the eight directions, looking at
+// most Range places away (0 for no limit, as in the puzzle)
+type VisibleRule struct {
+	Range int
+	Limit int
+}
+
+// Rule as given to -rules, with the range if there is one, e.g., visible:5
+// or visible3:5
+func (v VisibleRule) Name() string {
+	if v.Range == 0 {
+		return fmt.Sprintf("visible:%d", v.Limit)
+	}
+	return fmt.Sprintf("visible%d:%d", v.Range, v.Limit)
+}
+
+// Number of directions in which the first seat seen (within the range, if
+// any) is occupied
+func (v VisibleRule) Neighbours(lines [][]byte, r, c int) int {
+	if v.Range == 0 {
+		return adjacentOccupied2(lines, r, c)
+	}
+	return visibleOccupied(lines, r, c, v.Range)
+}
+
+// Occupied visible seats at which an occupied seat empties
+func (v VisibleRule) Tolerance() int {
+	return v.Limit
+}
+
+// Only the four seats immediately up/down/left/right (no diagonals)
+type VonNeumannRule struct {
+	Limit int
+}
+
+// Rule as given to -rules, e.g., vonneumann:3
+func (v VonNeumannRule) Name() string {
+	return fmt.Sprintf("vonneumann:%d", v.Limit)
+}
+
+// Number of the seats above, below, left and right that are occupied
+func (v VonNeumannRule) Neighbours(lines [][]byte, r, c int) int {
+	result := 0
+	for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		ri, ci := r+d[0], c+d[1]
+		if ri >= 0 && ri < len(lines) && ci >= 0 && ci < len(lines[ri]) && lines[ri][ci] == '#' {
+			result += 1
+		}
+	}
+	return result
+}
+
+// Occupied neighbours at which an occupied seat empties
+func (v VonNeumannRule) Tolerance() int {
+	return v.Limit
+}
+
+// Parse a rule given as name[:tolerance], where name is adjacent (Part 1,
+// tolerance 4 by default), visible (Part 2, tolerance 5), visibleN (like
+// visible, but only looking N places away, tolerance 5), or vonneumann
+// (tolerance 3)
+func parseRule(spec string) (SeatRule, error) {
+
+	// Split off the tolerance, if given
+	name, tol, hasTol := strings.Cut(strings.TrimSpace(spec), ":")
+	limit := 0
+	if hasTol {
+		n, err := strconv.Atoi(tol)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid tolerance in rule %q", spec)
+		}
+		limit = n
+	}
+
+	// Use the default tolerance for the rule if not given
+	orDefault := func(n int) int {
+		if limit == 0 {
+			return n
+		}
+		return limit
+	}
+
+	// Create the rule
+	switch {
+	case name == "adjacent":
+		return AdjacentRule{orDefault(4)}, nil
+	case name == "vonneumann":
+		return VonNeumannRule{orDefault(3)}, nil
+	case name == "visible":
+		return VisibleRule{0, orDefault(5)}, nil
+	case strings.HasPrefix(name, "visible"):
+		n, err := strconv.Atoi(name[7:])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid range in rule %q", spec)
+		}
+		return VisibleRule{n, orDefault(5)}, nil
+	}
+	return nil, fmt.Errorf("unknown rule %q", spec)
+}
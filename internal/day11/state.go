@@ -0,0 +1,22 @@
+// Encoding of the day 11 seating plan after each generation, for cycle and
+// fixed point detection (see internal/cycle)
+
+package day11
+
+import "bytes"
+
+// State of a board, as the rows one after another
+func boardState(lines [][]byte) []byte {
+	return bytes.Join(lines, []byte{'\n'})
+}
+
+// State of each seat in a graph, 1 for occupied
+func seatsState(state []bool) []byte {
+	buf := make([]byte, len(state))
+	for i, s := range state {
+		if s {
+			buf[i] = 1
+		}
+	}
+	return buf
+}
@@ -0,0 +1,285 @@
+// Advent of Code 2020, Day 12
+//
+// Simulate movement of a "ship" based on simple instructions, directly
+// for Part 1, relative to a "waypoint" for Part 2.
+//
+// Both parts run the same interpreter over the parsed instructions, with a
+// different "navigator" deciding what each instruction means. Headings and
+// waypoints are both vectors, rotated in steps of 90 degrees.
+//
+// AK, 14/01/2022 and 23/01/2022
+
+package day12
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// A parsed instruction, e.g., F10 is {'F', 10}
+type Instruction struct {
+	Op  byte
+	Arg int64
+}
+
+// A position or direction: +X is east (right), +Y is north (up)
+type Vector struct {
+	X, Y int64
+}
+
+// A navigator interprets instructions, moving the ship and keeping track of
+// its own direction of travel (a heading for Part 1, a waypoint for Part 2)
+type Navigator interface {
+	Execute(ins Instruction) error // apply one instruction
+	Ship() Vector                  // current position of the ship
+	Waypoint() (Vector, bool)      // absolute position of waypoint, if any
+}
+
+// Path of the ship: its starting position and its position after each
+// instruction, and the same for the waypoint if the navigator has one
+type Path struct {
+	Ship     []Vector
+	Waypoint []Vector
+}
+
+func Main(args []string) {
+
+	// Command line options
+	fs := flag.NewFlagSet("day12", flag.ExitOnError)
+	fname := fs.String("input", "input.txt", "input file")
+	export := fs.String("export", "", "write path of each part to <prefix>_partN.csv and .svg")
+	fs.Parse(args)
+
+	// Read file and parse instructions
+	t, _ := ioutil.ReadFile(*fname)
+	prog, err := parseInstructions(strings.Split(string(t), "\n"))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Do parts 1 and 2
+	for part, nav := range []Navigator{newHeadingNavigator(), newWaypointNavigator()} {
+		path, err := navigate(nav, prog)
+		if err != nil {
+			fmt.Printf("Part %d: %s\n", part+1, err)
+			continue
+		}
+		end := path.Ship[len(path.Ship)-1]
+		fmt.Printf("Part %d: ending position %d,%d after %d moves, distance = %d\n",
+			part+1, end.X, end.Y, len(path.Ship)-1, manhattan(end))
+
+		// Export the path if requested
+		if *export != "" {
+			prefix := fmt.Sprintf("%s_part%d", *export, part+1)
+			if err := exportPath(prefix, prog, path); err != nil {
+				fmt.Println("Error:", err)
+			}
+		}
+	}
+}
+
+// Run the instructions with the given navigator, returning the path of the
+// ship (and waypoint), i.e., the starting position and the position after
+// each instruction
+func navigate(nav Navigator, prog []Instruction) (Path, error) {
+	var path Path
+	record := func() {
+		path.Ship = append(path.Ship, nav.Ship())
+		if w, ok := nav.Waypoint(); ok {
+			path.Waypoint = append(path.Waypoint, w)
+		}
+	}
+	record()
+	for i, ins := range prog {
+		if err := nav.Execute(ins); err != nil {
+			return path, fmt.Errorf("instruction %d (%c%d): %v", i+1, ins.Op, ins.Arg, err)
+		}
+		record()
+	}
+	return path, nil
+}
+
+// Part 1: interpret instructions as simple movement of the ship N/E/S/W, or
+// changing direction left/right by x degrees, or move forward in current
+// direction
+type HeadingNavigator struct {
+	ship    Vector // position of the ship, starts at 0,0
+	heading Vector // unit vector of the direction the ship faces
+}
+
+// Ship starts at 0,0 facing east
+func newHeadingNavigator() *HeadingNavigator {
+	return &HeadingNavigator{heading: Vector{1, 0}}
+}
+
+// Execute one instruction, moving or turning the ship
+func (n *HeadingNavigator) Execute(ins Instruction) error {
+
+	// N/S/E/W : move north/south/east/west by given value
+	// L/R : turn left/right the given number of degrees.
+	// F : move forward by the given value in the current direction
+	if d, ok := compass(ins.Op); ok {
+		n.ship = add(n.ship, scale(d, ins.Arg))
+		return nil
+	}
+	switch ins.Op {
+	case 'L', 'R':
+		h, err := turn(n.heading, ins)
+		if err != nil {
+			return err
+		}
+		n.heading = h
+	case 'F':
+		n.ship = add(n.ship, scale(n.heading, ins.Arg))
+	default:
+		return fmt.Errorf("unknown instruction %c", ins.Op)
+	}
+	return nil
+}
+
+// Current position of the ship
+func (n *HeadingNavigator) Ship() Vector {
+	return n.ship
+}
+
+// No waypoint in Part 1
+func (n *HeadingNavigator) Waypoint() (Vector, bool) {
+	return Vector{}, false
+}
+
+// Part 2: interpret instructions as movement of a waypoint, except F, which
+// is movement of the ship towards the waypoint a number of times
+type WaypointNavigator struct {
+	ship     Vector // position of the ship, starts at 0,0
+	waypoint Vector // position of the waypoint, relative to the ship
+}
+
+// Initial position of the waypoint is 10 units east (right) and 1 unit north
+// (up), relative to the ship
+func newWaypointNavigator() *WaypointNavigator {
+	return &WaypointNavigator{waypoint: Vector{10, 1}}
+}
+
+// Execute one instruction, moving the waypoint or the ship
+func (n *WaypointNavigator) Execute(ins Instruction) error {
+
+	// N/S/E/W : move the waypoint north/south/east/west by given value,
+	//   does not move the ship
+	// L/R : rotate the waypoint around the ship left/right (counterclockwise
+	//   and clockwise) the given number of degrees; moves the waypoint,
+	//   but not the ship
+	// F : move ship forward to the waypoint a number of times equal to the
+	//   given value; each time, moves the ship the total distance between
+	//   the ship and the waypoint, but does not move the waypoint (since
+	//   it is always relative to the ship)
+	if d, ok := compass(ins.Op); ok {
+		n.waypoint = add(n.waypoint, scale(d, ins.Arg))
+		return nil
+	}
+	switch ins.Op {
+	case 'L', 'R':
+		w, err := turn(n.waypoint, ins)
+		if err != nil {
+			return err
+		}
+		n.waypoint = w
+	case 'F':
+		n.ship = add(n.ship, scale(n.waypoint, ins.Arg))
+	default:
+		return fmt.Errorf("unknown instruction %c", ins.Op)
+	}
+	return nil
+}
+
+// Current position of the ship
+func (n *WaypointNavigator) Ship() Vector {
+	return n.ship
+}
+
+// Absolute position of the waypoint
+func (n *WaypointNavigator) Waypoint() (Vector, bool) {
+	return add(n.ship, n.waypoint), true
+}
+
+// Parse lines into instructions, skipping blank lines
+func parseInstructions(lines []string) ([]Instruction, error) {
+	prog := []Instruction{}
+	for i, l := range lines {
+		if len(l) == 0 {
+			continue
+		}
+		if !strings.ContainsRune("NSEWLRF", rune(l[0])) {
+			return nil, fmt.Errorf("line %d: unknown instruction %q", i+1, l)
+		}
+		n, err := strconv.ParseInt(l[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid number in %q", i+1, l)
+		}
+		prog = append(prog, Instruction{l[0], n})
+	}
+	return prog, nil
+}
+
+// Unit vector for a compass direction N/S/E/W, false if not a direction
+func compass(op byte) (Vector, bool) {
+	switch op {
+	case 'N':
+		return Vector{0, 1}, true
+	case 'S':
+		return Vector{0, -1}, true
+	case 'E':
+		return Vector{1, 0}, true
+	case 'W':
+		return Vector{-1, 0}, true
+	}
+	return Vector{}, false
+}
+
+// Turn a vector according to an L or R instruction
+func turn(v Vector, ins Instruction) (Vector, error) {
+	if ins.Op == 'L' {
+		return rotate(v, -ins.Arg)
+	}
+	return rotate(v, ins.Arg)
+}
+
+// Rotate a vector clockwise around the origin by the given number of degrees
+// (negative for counterclockwise), which must be a multiple of 90
+func rotate(v Vector, degrees int64) (Vector, error) {
+	if degrees%90 != 0 {
+		return v, fmt.Errorf("invalid rotation %d, must be a multiple of 90", degrees)
+	}
+	quarters := (degrees/90%4 + 4) % 4 // number of clockwise quarter turns
+	for i := int64(0); i < quarters; i++ {
+		v = Vector{v.Y, -v.X}
+	}
+	return v, nil
+}
+
+// Add two vectors
+func add(a, b Vector) Vector {
+	return Vector{a.X + b.X, a.Y + b.Y}
+}
+
+// Multiply a vector by a number
+func scale(v Vector, n int64) Vector {
+	return Vector{v.X * n, v.Y * n}
+}
+
+// Manhattan distance from the origin
+func manhattan(v Vector) int64 {
+	return abs(v.X) + abs(v.Y)
+}
+
+// Simple absolute number
+func abs(a int64) int64 {
+	if a < 0 {
+		return -a
+	} else {
+		return a
+	}
+}
@@ -0,0 +1,246 @@
+// Unit tests and benchmarks for Day 12
+
+package day12
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Read and parse the puzzle input
+func readInput(tb testing.TB) []Instruction {
+	t, err := ioutil.ReadFile("input.txt")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	prog, err := parseInstructions(strings.Split(string(t), "\n"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return prog
+}
+
+// Both parts on the puzzle input
+func TestInput(t *testing.T) {
+	prog := readInput(t)
+	for part, tc := range []struct {
+		nav  Navigator
+		want int64
+	}{
+		{newHeadingNavigator(), 362},
+		{newWaypointNavigator(), 29895},
+	} {
+		path, err := navigate(tc.nav, prog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := manhattan(path.Ship[len(path.Ship)-1]); d != tc.want {
+			t.Errorf("Part %d distance should be %d, not %d", part+1, tc.want, d)
+		}
+	}
+}
+
+// Valid lines should parse, skipping blank lines, and anything else should
+// be an error giving the line number
+func TestParseInstructions(t *testing.T) {
+	prog, err := parseInstructions([]string{"F10", "", "R90", "N-3"})
+	if err != nil || len(prog) != 3 || prog[0] != (Instruction{'F', 10}) ||
+		prog[1] != (Instruction{'R', 90}) || prog[2] != (Instruction{'N', -3}) {
+		t.Error("Should be F10, R90, N-3, not", prog, err)
+	}
+	for _, tc := range []struct {
+		lines []string
+		want  string
+	}{
+		{[]string{"X10"}, "line 1: unknown instruction"},
+		{[]string{"f10"}, "line 1: unknown instruction"},
+		{[]string{"F10", "", "F"}, "line 3: invalid number"},
+		{[]string{"R9x"}, "line 1: invalid number"},
+		{[]string{"N 5"}, "line 1: invalid number"},
+		{[]string{"E99999999999999999999"}, "line 1: invalid number"},
+	} {
+		_, err := parseInstructions(tc.lines)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q should give %q, not %v", tc.lines, tc.want, err)
+		}
+	}
+}
+
+// Rotations by multiples of 90 degrees in either direction, and anything
+// else is an error
+func TestRotate(t *testing.T) {
+	v := Vector{10, 4}
+	for _, tc := range []struct {
+		degrees int64
+		want    Vector
+	}{
+		{0, Vector{10, 4}},
+		{90, Vector{4, -10}},
+		{180, Vector{-10, -4}},
+		{270, Vector{-4, 10}},
+		{360, Vector{10, 4}},
+		{-90, Vector{-4, 10}},
+		{-180, Vector{-10, -4}},
+		{-270, Vector{4, -10}},
+		{450, Vector{4, -10}},
+	} {
+		r, err := rotate(v, tc.degrees)
+		if err != nil || r != tc.want {
+			t.Errorf("Rotate %v by %d should be %v, not %v (%v)", v, tc.degrees, tc.want, r, err)
+		}
+	}
+	for _, degrees := range []int64{45, -45, 1, 100, 269} {
+		if r, err := rotate(v, degrees); err == nil {
+			t.Errorf("Rotate by %d should be an error, not %v", degrees, r)
+		}
+	}
+}
+
+// Both navigators should turn left and right the same way, and reject
+// rotations that are not multiples of 90 degrees and unknown actions
+// without moving
+func TestExecute(t *testing.T) {
+	for _, tc := range []struct {
+		ins      Instruction
+		heading  Vector // heading of the ship in Part 1, starting east
+		waypoint Vector // waypoint relative to the ship in Part 2, starting 10,1
+	}{
+		{Instruction{'R', 90}, Vector{0, -1}, Vector{1, -10}},
+		{Instruction{'L', 90}, Vector{0, 1}, Vector{-1, 10}},
+		{Instruction{'R', 180}, Vector{-1, 0}, Vector{-10, -1}},
+		{Instruction{'L', 270}, Vector{0, -1}, Vector{1, -10}},
+		{Instruction{'R', -90}, Vector{0, 1}, Vector{-1, 10}},
+	} {
+		h := newHeadingNavigator()
+		if err := h.Execute(tc.ins); err != nil || h.heading != tc.heading {
+			t.Errorf("%c%d should face %v, not %v (%v)", tc.ins.Op, tc.ins.Arg, tc.heading, h.heading, err)
+		}
+		w := newWaypointNavigator()
+		if err := w.Execute(tc.ins); err != nil || w.waypoint != tc.waypoint {
+			t.Errorf("%c%d should move waypoint to %v, not %v (%v)", tc.ins.Op, tc.ins.Arg, tc.waypoint, w.waypoint, err)
+		}
+	}
+	for _, nav := range []Navigator{newHeadingNavigator(), newWaypointNavigator()} {
+		for _, ins := range []Instruction{{'R', 45}, {'L', 135}, {'X', 1}, {'f', 1}} {
+			if err := nav.Execute(ins); err == nil {
+				t.Errorf("%T: %c%d should be an error", nav, ins.Op, ins.Arg)
+			}
+		}
+		if w, _ := nav.Waypoint(); nav.Ship() != (Vector{}) || (w != Vector{} && w != Vector{10, 1}) {
+			t.Errorf("%T: invalid instructions should not move anything", nav)
+		}
+		if _, err := navigate(nav, []Instruction{{'F', 1}, {'R', 45}}); err == nil ||
+			!strings.Contains(err.Error(), "instruction 2") {
+			t.Errorf("%T: should fail at instruction 2, not %v", nav, err)
+		}
+	}
+}
+
+// The CSV should have a row for each position in the path, with the
+// waypoint if there is one, and the SVG should plot the ship's path (and the
+// waypoint's) with markers at the start and end, for the sample
+func TestExport(t *testing.T) {
+	data, err := ioutil.ReadFile("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog, err := parseInstructions(strings.Split(string(data), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, nav := range []Navigator{newHeadingNavigator(), newWaypointNavigator()} {
+		path, err := navigate(nav, prog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prefix := t.TempDir() + "/path"
+		if err := exportPath(prefix, prog, path); err != nil {
+			t.Fatal(err)
+		}
+
+		// CSV: header, then the start and each instruction
+		f, err := os.Open(prefix + ".csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := csv.NewReader(f).ReadAll()
+		f.Close()
+		if err != nil || len(rows) != len(prog)+2 {
+			t.Fatalf("%T: CSV should have %d rows, not %d (%v)", nav, len(prog)+2, len(rows), err)
+		}
+		for i, s := range path.Ship {
+			want := []string{fmt.Sprint(i), "", fmt.Sprint(s.X), fmt.Sprint(s.Y), "", ""}
+			if i > 0 {
+				want[1] = fmt.Sprintf("%c%d", prog[i-1].Op, prog[i-1].Arg)
+			}
+			if i < len(path.Waypoint) {
+				want[4], want[5] = fmt.Sprint(path.Waypoint[i].X), fmt.Sprint(path.Waypoint[i].Y)
+			}
+			if strings.Join(rows[i+1], ",") != strings.Join(want, ",") {
+				t.Errorf("%T: CSV row %d should be %v, not %v", nav, i+1, want, rows[i+1])
+			}
+		}
+
+		// SVG: one polyline for each path, and markers at each end
+		data, _ := os.ReadFile(prefix + ".svg")
+		svg := string(data)
+		lines := 1
+		if len(path.Waypoint) > 0 {
+			lines = 2
+		}
+		end := path.Ship[len(path.Ship)-1]
+		if n := strings.Count(svg, "<polyline"); n != lines {
+			t.Errorf("%T: SVG should have %d polylines, not %d", nav, lines, n)
+		}
+		for _, want := range []string{"stroke=\"blue\"", ">start 0,0</text>",
+			fmt.Sprintf(">end %d,%d</text>", end.X, end.Y)} {
+			if !strings.Contains(svg, want) {
+				t.Errorf("%T: SVG should contain %s", nav, want)
+			}
+		}
+		if len(path.Waypoint) > 0 && !strings.Contains(svg, "stroke=\"orange\"") {
+			t.Errorf("%T: SVG should have the waypoint path", nav)
+		}
+	}
+}
+
+// Errors creating or writing either file should be returned
+func TestExportErrors(t *testing.T) {
+	prog := []Instruction{{'F', 10}}
+	path, _ := navigate(newWaypointNavigator(), prog)
+	missing := t.TempDir() + "/missing/path"
+	if err := writeCSV(missing+".csv", prog, path); err == nil {
+		t.Error("CSV in a missing directory should be an error")
+	}
+	if err := writeSVG(missing+".svg", path); err == nil {
+		t.Error("SVG in a missing directory should be an error")
+	}
+	if _, err := os.Stat("/dev/full"); err == nil {
+		if err := writeCSV("/dev/full", prog, path); err == nil {
+			t.Error("Writing CSV to a full device should be an error")
+		}
+		if err := writeSVG("/dev/full", path); err == nil {
+			t.Error("Writing SVG to a full device should be an error")
+		}
+	}
+}
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	prog := readInput(b)
+	for i := 0; i < b.N; i++ {
+		navigate(newHeadingNavigator(), prog)
+	}
+}
+
+// Part 2 on the puzzle input
+func BenchmarkPart2(b *testing.B) {
+	prog := readInput(b)
+	for i := 0; i < b.N; i++ {
+		navigate(newWaypointNavigator(), prog)
+	}
+}
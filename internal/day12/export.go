@@ -0,0 +1,129 @@
+// Export the path of the ship (and waypoint) for day 12, as a CSV file and as
+// an SVG plot, to visually check the rotation semantics
+
+package day12
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Size of SVG plot, and margin around the path, in pixels
+const (
+	svgSize   = 800
+	svgMargin = 40
+)
+
+// Write path to <prefix>.csv and <prefix>.svg
+func exportPath(prefix string, prog []Instruction, path Path) error {
+	if err := writeCSV(prefix+".csv", prog, path); err != nil {
+		return err
+	}
+	return writeSVG(prefix+".svg", path)
+}
+
+// Write one row per step: the instruction (blank for the starting position),
+// position of the ship, and position of the waypoint if there is one
+func writeCSV(filename string, prog []Instruction, path Path) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+
+	fmt.Fprintln(w, "step,instruction,ship_x,ship_y,waypoint_x,waypoint_y")
+	for i, s := range path.Ship {
+		ins := ""
+		if i > 0 {
+			ins = fmt.Sprintf("%c%d", prog[i-1].Op, prog[i-1].Arg)
+		}
+		fmt.Fprintf(w, "%d,%s,%d,%d", i, ins, s.X, s.Y)
+		if i < len(path.Waypoint) {
+			fmt.Fprintf(w, ",%d,%d\n", path.Waypoint[i].X, path.Waypoint[i].Y)
+		} else {
+			fmt.Fprintln(w, ",,")
+		}
+	}
+	return closeWriter(f, w)
+}
+
+// Plot the path of the ship as a polyline, with the waypoint's path (if any)
+// dashed behind it, markers at the start and end, and a scale bar. North is
+// up, east is right.
+func writeSVG(filename string, path Path) error {
+
+	// Find extent of everything to be plotted, and scale to fit
+	minX, minY, maxX, maxY := int64(0), int64(0), int64(0), int64(0)
+	for _, v := range append(append([]Vector{}, path.Ship...), path.Waypoint...) {
+		minX, maxX = min(minX, v.X), max(maxX, v.X)
+		minY, maxY = min(minY, v.Y), max(maxY, v.Y)
+	}
+	span := max(maxX-minX, maxY-minY, 1)
+	scale := float64(svgSize-2*svgMargin) / float64(span)
+
+	// Convert a position to pixel coordinates (SVG y increases downwards)
+	px := func(v Vector) (float64, float64) {
+		return svgMargin + float64(v.X-minX)*scale,
+			svgMargin + float64(maxY-v.Y)*scale
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\">\n", svgSize, svgSize)
+	fmt.Fprintln(w, "<rect width=\"100%\" height=\"100%\" fill=\"white\"/>")
+
+	// Waypoint path, then ship path on top
+	polyline := func(vs []Vector, style string) {
+		fmt.Fprint(w, "<polyline fill=\"none\" ", style, " points=\"")
+		for _, v := range vs {
+			x, y := px(v)
+			fmt.Fprintf(w, "%.1f,%.1f ", x, y)
+		}
+		fmt.Fprintln(w, "\"/>")
+	}
+	if len(path.Waypoint) > 0 {
+		polyline(path.Waypoint, "stroke=\"orange\" stroke-width=\"1\" stroke-dasharray=\"4,3\"")
+	}
+	polyline(path.Ship, "stroke=\"blue\" stroke-width=\"1.5\"")
+
+	// Start and end markers
+	marker := func(v Vector, colour, label string) {
+		x, y := px(v)
+		fmt.Fprintf(w, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"5\" fill=\"%s\"/>\n", x, y, colour)
+		fmt.Fprintf(w, "<text x=\"%.1f\" y=\"%.1f\" font-size=\"12\" fill=\"%s\">%s %d,%d</text>\n",
+			x+8, y-8, colour, label, v.X, v.Y)
+	}
+	marker(path.Ship[0], "green", "start")
+	marker(path.Ship[len(path.Ship)-1], "red", "end")
+
+	// Scale bar: the largest power of 10 units that fits in a quarter of
+	// the plot
+	units := math.Pow(10, math.Floor(math.Log10(float64(span)/4)))
+	if units < 1 {
+		units = 1
+	}
+	y := float64(svgSize - svgMargin/2)
+	fmt.Fprintf(w, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"black\" stroke-width=\"2\"/>\n",
+		svgMargin, y, svgMargin+units*scale, y)
+	fmt.Fprintf(w, "<text x=\"%d\" y=\"%.1f\" font-size=\"12\">%.0f units</text>\n",
+		svgMargin, y-6, units)
+
+	fmt.Fprintln(w, "</svg>")
+	return closeWriter(f, w)
+}
+
+// Flush buffered output and close the file, returning the first error. The
+// buffer keeps the first write error, so it only needs checking once.
+func closeWriter(f *os.File, w *bufio.Writer) error {
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
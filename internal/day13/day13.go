@@ -10,7 +10,7 @@
 //
 // AK, 24/01/2022
 
-package main
+package day13
 
 import (
 	"fmt"
@@ -19,17 +19,32 @@ import (
 	"strings"
 )
 
-func main() {
+func Main(args []string) {
+
+	// Read departure time and list of buses
+	buses, dep0 := readSchedule("input.txt")
+	fmt.Printf("Departure = %d mins since midnight\n", dep0)
+	fmt.Println("Buses:", buses)
+
+	// Do Part 1
+	part1(buses, dep0)
+
+	// Do Part 2
+	part2(buses)
+}
+
+// Read the departure time (in minutes since midnight) and list of buses,
+// with -1 for each 'x'
+func readSchedule(filename string) ([]int64, int64) {
 
 	// Read file and split into lines
-	t, _ := ioutil.ReadFile("input.txt")
+	t, _ := ioutil.ReadFile(filename)
 	lines := strings.Split(string(t), "\n")
 
 	// Line 1 has departure time, used only for Part 1 (convert
 	// to minutes since midnight)
 	dep, _ := strconv.ParseInt(lines[0], 10, 64)
 	dep0 := int64(dep/60)*60 + dep%60
-	fmt.Printf("Departure %d = %d mins since midnight\n", dep, dep0)
 
 	// Line 2 has list of buses (numbers of minutes, or 'x' if no bus,
 	// replace these with -1)
@@ -43,13 +58,7 @@ func main() {
 			buses = append(buses, busNo)
 		}
 	}
-	fmt.Println("Buses:", buses)
-
-	// Do Part 1
-	part1(buses, dep0)
-
-	// Do Part 2
-	part2(buses)
+	return buses, dep0
 }
 
 // Part 1: find the earliest bus that departs after designated time
@@ -1,10 +1,12 @@
-// These are unit tests for Day 13
+// These are unit tests and benchmarks for Day 13
 
-package main
+package day13
 
 import (
 	"fmt"
 	"testing"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/testutil"
 )
 
 // Part 2 test examples, from problem definition
@@ -38,3 +40,23 @@ func TestPart2(t *testing.T) {
 	}
 
 }
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	buses, dep0 := readSchedule("input.txt")
+	testutil.Quiet(func() {
+		for i := 0; i < b.N; i++ {
+			part1(buses, dep0)
+		}
+	})
+}
+
+// Part 2 on the largest example, since the puzzle input takes over an hour
+func BenchmarkPart2(b *testing.B) {
+	buses := []int64{1789, 37, 47, 1889}
+	testutil.Quiet(func() {
+		for i := 0; i < b.N; i++ {
+			part2(buses)
+		}
+	})
+}
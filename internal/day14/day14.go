@@ -8,25 +8,49 @@
 //
 // AK, 15/10/2022
 
-package main
+package day14
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"strconv"
 	"strings"
 )
 
-func main() {
+func Main(args []string) {
 
-	var mask string           // Current value of mask
-	mem1 := map[int64]int64{} // Part 1: current number at each location
-	mem2 := map[int64]int64{} // Same for Part 2
+	// Command line options
+	fs := flag.NewFlagSet("day14", flag.ExitOnError)
+	fname := fs.String("input", "input.txt", "input file")
+	trace := fs.Bool("trace", false, "trace each memory write")
+	fs.Parse(args)
 
 	// Read input file
-	data, _ := ioutil.ReadFile("input.txt")
+	data, _ := ioutil.ReadFile(*fname)
 	lines := strings.Split(string(data), "\n")
 
+	// Optionally show what each instruction does
+	if *trace {
+		traceProgram(lines)
+		return
+	}
+
+	// Part 1: apply mask to values
+	fmt.Println("Part 1:", part1(lines))
+
+	// Part 2: apply mask to addresses, with concrete and symbolic memory
+	fmt.Println("Part 2:", part2(lines))
+	fmt.Println("Part 2 (floating):", part2Floating(lines))
+}
+
+// Part 1: apply mask to each number, set memory locations, and sum up
+// contents of memory
+func part1(lines []string) int64 {
+
+	var mask string          // Current value of mask
+	mem := map[int64]int64{} // Current number at each location
+
 	// Go through line by line
 	for _, l := range lines {
 
@@ -37,41 +61,75 @@ func main() {
 		}
 
 		// Other lines must set memory, with address and value
-		if !strings.HasPrefix(l, "mem[") {
-			fmt.Println("Skipping invalid line:", l)
+		addr, val, ok := parseMem(l)
+		if !ok {
+			continue
+		}
+
+		// Apply mask to the current number, and set memory location
+		mem[addr] = applyMaskToNum(mask, val)
+	}
+
+	// Sum up contents of memory
+	var tot int64
+	for _, v := range mem {
+		tot += v
+	}
+	return tot
+}
+
+// Part 2: apply mask to address (using different rules than Part 1), then
+// expand address so all possible 1/0 values of 'X' digits, and set memory
+// location (unchanged); sum up contents of memory
+func part2(lines []string) int64 {
+
+	var mask string          // Current value of mask
+	mem := map[int64]int64{} // Current number at each location
+
+	// Go through line by line
+	for _, l := range lines {
+
+		// Parse lines with mask, set current mask
+		if strings.HasPrefix(l, "mask = ") {
+			mask = l[7:]
 			continue
 		}
-		addr := atoi(l[4:strings.Index(l, "]")]) // address between brackets
-		val := atoi(l[strings.Index(l, "=")+2:]) // value after equal sign
 
-		// Part 1: apply mask to the current number, and set memory location
-		n := applyMaskToNum(mask, val)
-		mem1[addr] = n
+		// Other lines must set memory, with address and value
+		addr, val, ok := parseMem(l)
+		if !ok {
+			continue
+		}
 
-		// Part 2: apply mask to address (using different rules than Part 1),
-		// then expand address so all possible 1/0 values of 'X' digits,
-		// and set memory location (unchanged)
+		// Set every address the masked address expands to
 		addrMasked := applyMaskToAddr(mask, addr)
 		addrs := expandMask(addrMasked)
 		for _, a := range addrs {
 			a1 := btoi(a)
-			mem2[a1] = val
+			mem[a1] = val
 		}
 	}
 
-	// Part 1: Sum up contents of memory
+	// Sum up contents of memory
 	var tot int64
-	for _, v := range mem1 {
+	for _, v := range mem {
 		tot += v
 	}
-	fmt.Println("Part 1:", tot)
+	return tot
+}
 
-	// Part 2: Sum up contents of memory
-	var tot2 int64
-	for _, v := range mem2 {
-		tot2 += v
+// Parse a line that sets memory, returns address and value, and false if
+// the line is not valid
+func parseMem(l string) (int64, int64, bool) {
+	if !strings.HasPrefix(l, "mem[") {
+		if len(l) > 0 {
+			fmt.Println("Skipping invalid line:", l)
+		}
+		return 0, 0, false
 	}
-	fmt.Println("Part 2:", tot2)
+	addr := atoi(l[4:strings.Index(l, "]")]) // address between brackets
+	val := atoi(l[strings.Index(l, "=")+2:]) // value after equal sign
+	return addr, val, true
 }
 
 // Part 1: Apply binary mask to a number, settings 1/0 according to mask, and
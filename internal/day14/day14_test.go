@@ -0,0 +1,116 @@
+// These are unit tests and benchmarks for Day 14
+
+package day14
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/testutil"
+)
+
+// Part 2 example from problem definition, using symbolic memory
+func TestPart2FloatingExample(t *testing.T) {
+	lines := []string{
+		"mask = 000000000000000000000000000000X1001X",
+		"mem[42] = 100",
+		"mask = 00000000000000000000000000000000X0XX",
+		"mem[26] = 1",
+	}
+	res := part2Floating(lines)
+	if res != 208 {
+		t.Error("Error processing Part 2 example")
+		fmt.Printf("Expected %d, got %d\n", 208, res)
+	}
+}
+
+// Symbolic memory should give the same answer as concrete memory on the
+// puzzle input
+func TestPart2FloatingInput(t *testing.T) {
+	data, _ := ioutil.ReadFile("input.txt")
+	lines := strings.Split(string(data), "\n")
+	sb := part2(lines)
+	res := part2Floating(lines)
+	if res != sb {
+		t.Error("Symbolic memory does not match concrete memory")
+		fmt.Printf("Expected %d, got %d\n", sb, res)
+	}
+}
+
+// Tracing the examples from the problem description should show each value
+// before and after masking with the changed bits marked, the addresses each
+// write expands to, and the memory used by each part
+func TestTrace(t *testing.T) {
+	data, err := ioutil.ReadFile("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		lines []string
+		want  []string
+	}{
+		{strings.Split(string(data), "\n"), []string{
+			"mem[8] = 11\n" +
+				"  value  000000000000000000000000000000001011  11\n" +
+				"  masked 000000000000000000000000000001001001  73\n" +
+				"                                      ^    ^ \n",
+			"  masked 000000000000000000000000000001000000  64\n",
+			"  Part 1: 2 addresses written by 3 instructions\n",
+		}},
+		{[]string{
+			"mask = 000000000000000000000000000000X1001X",
+			"mem[42] = 100",
+			"mask = 00000000000000000000000000000000X0XX",
+			"mem[26] = 1",
+		}, []string{
+			"  addr   000000000000000000000000000000101010  42\n" +
+				"  floats 000000000000000000000000000000X1101X  4 addresses\n" +
+				"                                       ^^   ^\n",
+			"  floats 00000000000000000000000000000001X0XX  8 addresses\n",
+			"Memory footprint:\n" +
+				"  Part 1: 2 addresses written by 2 instructions\n" +
+				"  Part 2: 12 address writes, 10 distinct addresses, held in 2 patterns\n",
+		}},
+	} {
+		out := testutil.Capture(func() { traceProgram(tc.lines) })
+		for _, want := range tc.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("Trace should contain:\n%s\nnot:\n%s", want, out)
+			}
+		}
+	}
+}
+
+// Changed positions are marked, up to the length of the shorter string
+func TestMarkChanges(t *testing.T) {
+	for _, tc := range [][3]string{
+		{"0101", "0101", "    "},
+		{"0101", "1100", "^  ^"},
+		{"0101", "X1X1", "^ ^ "},
+		{"0101", "01", "    "},
+	} {
+		if got := markChanges(tc[0], tc[1]); got != tc[2] {
+			t.Errorf("%s to %s should be %q, not %q", tc[0], tc[1], tc[2], got)
+		}
+	}
+}
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < b.N; i++ {
+		part1(lines)
+	}
+}
+
+// Part 2 on the puzzle input, with concrete memory as the program runs it
+func BenchmarkPart2(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < b.N; i++ {
+		part2(lines)
+	}
+}
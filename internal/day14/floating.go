@@ -0,0 +1,113 @@
+// Symbolic "floating address" memory for Part 2 of day 14
+//
+// Instead of expanding each masked address into 2^k concrete addresses,
+// every write is stored as a pattern of 1/0/X together with its value. A new
+// write carves its pattern out of all earlier writes, so the stored patterns
+// never overlap, and the sum of memory is simply the value of each pattern
+// multiplied by the number of addresses it covers. This works even for masks
+// with many X digits, where expanding the addresses would be infeasible.
+
+package day14
+
+import "strings"
+
+// A write to memory: the address pattern (1/0/X), and the value written
+type FloatingWrite struct {
+	Pattern string
+	Val     int64
+}
+
+// Part 2 using symbolic memory, should give the same answer as part2()
+func part2Floating(lines []string) int64 {
+
+	var mask string          // Current value of mask
+	mem := []FloatingWrite{} // Non-overlapping writes, in order
+
+	// Go through line by line
+	for _, l := range lines {
+
+		// Parse lines with mask, set current mask
+		if strings.HasPrefix(l, "mask = ") {
+			mask = l[7:]
+			continue
+		}
+
+		// Other lines must set memory, with address and value
+		addr, val, ok := parseMem(l)
+		if !ok {
+			continue
+		}
+
+		// Store the masked address pattern, without expanding it
+		mem = floatingSet(mem, applyMaskToAddr(mask, addr), val)
+	}
+
+	// Sum up contents of memory
+	return floatingSum(mem)
+}
+
+// Write a value to all addresses matching a pattern: remove the pattern from
+// all earlier writes (which may split them into several pieces), then add
+// the new write; returns the new list of writes
+func floatingSet(mem []FloatingWrite, pattern string, val int64) []FloatingWrite {
+	mem2 := []FloatingWrite{}
+	for _, w := range mem {
+		for _, p := range subtractPattern(w.Pattern, pattern) {
+			mem2 = append(mem2, FloatingWrite{p, w.Val})
+		}
+	}
+	return append(mem2, FloatingWrite{pattern, val})
+}
+
+// Sum up contents of symbolic memory, i.e., each value times the number of
+// addresses its pattern covers
+func floatingSum(mem []FloatingWrite) int64 {
+	var tot int64
+	for _, w := range mem {
+		tot += w.Val * countAddrs(w.Pattern)
+	}
+	return tot
+}
+
+// Subtract pattern b from pattern a, returning a list of non-overlapping
+// patterns that together cover all addresses matching a but not b
+func subtractPattern(a, b string) []string {
+
+	// If the patterns disagree on any fixed digit, they do not overlap, so
+	// a is unchanged
+	for i := 0; i < len(a); i++ {
+		if a[i] != 'X' && b[i] != 'X' && a[i] != b[i] {
+			return []string{a}
+		}
+	}
+
+	// Otherwise, go through each X in a where b has a fixed digit: split off
+	// the half of a that has the opposite digit (which cannot match b), and
+	// keep narrowing down the rest to match b's digit. Whatever is left at
+	// the end is covered by b, and is dropped.
+	result := []string{}
+	rest := []byte(a)
+	for i := 0; i < len(a); i++ {
+		if rest[i] != 'X' || b[i] == 'X' {
+			continue
+		}
+		piece := []byte(string(rest))
+		piece[i] = flipBit(b[i])
+		result = append(result, string(piece))
+		rest[i] = b[i]
+	}
+	return result
+}
+
+// Number of concrete addresses covered by a pattern, i.e., 2^(number of X)
+func countAddrs(pattern string) int64 {
+	return int64(1) << strings.Count(pattern, "X")
+}
+
+// Opposite of a binary digit
+func flipBit(c byte) byte {
+	if c == '0' {
+		return '1'
+	}
+	return '0'
+}
@@ -0,0 +1,80 @@
+// Trace mode for day 14: disassemble the program, showing what each memory
+// write does under the two different mask semantics, i.e., applyMaskToNum()
+// for Part 1 and applyMaskToAddr() for Part 2
+
+package day14
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Print each instruction with the active mask, the value before and after
+// masking with changed bits marked, and the number of addresses written in
+// Part 2; finish with a summary of the memory footprint
+func traceProgram(lines []string) {
+
+	var mask string           // Current value of mask
+	mem1 := map[int64]bool{}  // Part 1: addresses written
+	mem2 := []FloatingWrite{} // Part 2: address patterns written
+	var writes, touched int64 // Part 2: number of writes, addresses touched
+
+	// Go through line by line
+	for _, l := range lines {
+
+		// Show each new mask
+		if strings.HasPrefix(l, "mask = ") {
+			mask = l[7:]
+			fmt.Println("mask    ", mask)
+			continue
+		}
+
+		// Other lines must set memory, with address and value
+		addr, val, ok := parseMem(l)
+		if !ok {
+			continue
+		}
+		fmt.Println(l)
+
+		// Part 1: value before and after applying mask
+		n := applyMaskToNum(mask, val)
+		before := fmt.Sprintf("%036b", val)
+		after := fmt.Sprintf("%036b", n)
+		fmt.Printf("  value  %s  %d\n", before, val)
+		fmt.Printf("  masked %s  %d\n", after, n)
+		fmt.Printf("         %s\n", markChanges(before, after))
+		mem1[addr] = true
+
+		// Part 2: address before and after applying mask, and number of
+		// addresses this expands to
+		pattern := applyMaskToAddr(mask, addr)
+		a := fmt.Sprintf("%036b", addr)
+		fmt.Printf("  addr   %s  %d\n", a, addr)
+		fmt.Printf("  floats %s  %d addresses\n", pattern, countAddrs(pattern))
+		fmt.Printf("         %s\n", markChanges(a, pattern))
+		mem2 = floatingSet(mem2, pattern, val)
+		writes++
+		touched += countAddrs(pattern)
+	}
+
+	// Summary of memory used by each part
+	var distinct int64
+	for _, w := range mem2 {
+		distinct += countAddrs(w.Pattern)
+	}
+	fmt.Println("\nMemory footprint:")
+	fmt.Println("  Part 1:", len(mem1), "addresses written by", writes, "instructions")
+	fmt.Println("  Part 2:", touched, "address writes,", distinct,
+		"distinct addresses, held in", len(mem2), "patterns")
+}
+
+// Mark the positions where two binary strings differ with '^'
+func markChanges(before, after string) string {
+	marks := []byte(strings.Repeat(" ", len(before)))
+	for i := 0; i < len(before) && i < len(after); i++ {
+		if before[i] != after[i] {
+			marks[i] = '^'
+		}
+	}
+	return string(marks)
+}
@@ -0,0 +1,144 @@
+// Analysis of the day 15 memory game (the Van Eck sequence) beyond the
+// puzzle: optionally stream the sequence to a file, report statistics at
+// regular checkpoints, and look for repeats of the start of the sequence
+
+package day15
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Play the game for the given number of turns, reporting statistics every
+// so many turns, and looking for later occurrences of the first prefixLen
+// numbers; if outFile is not blank, write each number spoken to it
+func analyseSequence(input []int, iters, every, prefixLen int, outFile string) error {
+
+	// Check options before creating the output file
+	if every < 1 {
+		return fmt.Errorf("invalid number of turns between reports %d, must be at least 1", every)
+	}
+	if prefixLen < 1 {
+		return fmt.Errorf("invalid prefix length %d, must be at least 1", prefixLen)
+	}
+	g, err := newGame(input, iters)
+	if err != nil {
+		return err
+	}
+
+	// Open output file if required
+	var w *bufio.Writer
+	if outFile != "" {
+		f, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = bufio.NewWriter(f)
+	}
+
+	// Get the prefix to look for, by playing the first few turns, and
+	// prepare it for matching
+	if prefixLen > iters {
+		prefixLen = iters
+	}
+	prefix := make([]int, prefixLen)
+	pg, _ := newGame(input, prefixLen) // valid, since no more than iters
+	for i := range prefix {
+		prefix[i] = pg.Next()
+	}
+	fail := failureFunction(prefix)
+	fmt.Println("Looking for repeats of prefix", prefix)
+
+	// Statistics collected
+	seen := make([]bool, len(g.lastTurn)) // numbers spoken so far
+	var distinct, zeros int               // distinct numbers, number of zeros
+	var maxGap, maxGapTurn int            // largest gap, and when it was spoken
+	var matched int                       // length of prefix matched so far
+	var longest, longestTurn int          // longest partial repeat of prefix, and where it started
+	var repeats, firstRepeat int          // full repeats of prefix, and where the first started
+
+	// Play each turn
+	for g.turn < iters {
+		n := g.Next()
+
+		// Write number to output file
+		if w != nil {
+			w.WriteString(strconv.Itoa(n))
+			w.WriteByte('\n')
+		}
+
+		// Update counts; after the starting numbers, each number spoken is
+		// the gap since the last number was previously spoken
+		if !seen[n] {
+			seen[n] = true
+			distinct++
+		}
+		if n == 0 {
+			zeros++
+		}
+		if g.turn > len(input) && n > maxGap {
+			maxGap, maxGapTurn = n, g.turn
+		}
+
+		// Extend the match against the prefix (Knuth-Morris-Pratt), for
+		// occurrences starting after the first turn
+		if g.turn > 1 {
+			for matched > 0 && (matched == prefixLen || prefix[matched] != n) {
+				matched = fail[matched-1]
+			}
+			if prefix[matched] == n {
+				matched++
+			}
+			if matched > longest {
+				longest, longestTurn = matched, g.turn-matched+1
+			}
+			if matched == prefixLen {
+				repeats++
+				if firstRepeat == 0 {
+					firstRepeat = g.turn - matched + 1
+				}
+			}
+		}
+
+		// Report statistics at each checkpoint, and at the end
+		if g.turn%every == 0 || g.turn == iters {
+			fmt.Printf("Turn %d: %d distinct, largest gap %d (turn %d), zeros %d (%.2f%%), longest prefix repeat %d (turn %d)\n",
+				g.turn, distinct, maxGap, maxGapTurn, zeros,
+				float64(zeros)*100/float64(g.turn), longest, longestTurn)
+		}
+	}
+
+	// Report whether the whole prefix ever repeated
+	if repeats > 0 {
+		fmt.Printf("Prefix of length %d repeats %d times, first at turn %d\n",
+			prefixLen, repeats, firstRepeat)
+	} else {
+		fmt.Printf("Prefix of length %d does not repeat in %d turns\n", prefixLen, iters)
+	}
+
+	// Make sure all output was written
+	if w != nil {
+		return w.Flush()
+	}
+	return nil
+}
+
+// KMP failure function: for each position i in the pattern, the length of
+// the longest proper prefix of pattern[:i+1] that is also a suffix of it
+func failureFunction(pattern []int) []int {
+	fail := make([]int, len(pattern))
+	k := 0
+	for i := 1; i < len(pattern); i++ {
+		for k > 0 && pattern[i] != pattern[k] {
+			k = fail[k-1]
+		}
+		if pattern[i] == pattern[k] {
+			k++
+		}
+		fail[i] = k
+	}
+	return fail
+}
@@ -0,0 +1,243 @@
+// Advent of Code 2020, Day 15
+//
+// Simulate a convoluted memory game, which becomes infeasible using a simple
+// list of history, when the number of iterations goes from 2020 (part 1) to
+// 30 million (part 2).
+//
+// AK, 15/10/2022
+
+package day15
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func Main(args []string) {
+
+	// Options for analysing the sequence instead of solving the puzzle
+	fs := flag.NewFlagSet("day15", flag.ExitOnError)
+	analyse := fs.Bool("analyse", false, "report statistics about the sequence")
+	out := fs.String("out", "", "when analysing, write the sequence to this file")
+	every := fs.Int("every", 1000000, "when analysing, report statistics every N turns")
+	prefix := fs.Int("prefix", 10, "when analysing, length of prefix to look for repeats of")
+	fs.Parse(args)
+	args = fs.Args()
+
+	// Starting numbers are the first argument, separated by commas, e.g.,
+	// 11,18,0,20,1,7,16; optional second argument is number of iterations
+	if len(args) < 1 {
+		fmt.Println("Usage: aoc 15 [options] <starting numbers> [iterations]")
+		fs.PrintDefaults()
+		return
+	}
+	input := []int{}
+	for _, s := range strings.Split(args[0], ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			fmt.Println("Invalid starting number:", s)
+			return
+		}
+		input = append(input, n)
+	}
+
+	// Number of iterations, if given
+	iters := 0
+	if len(args) > 1 {
+		var err error
+		iters, err = strconv.Atoi(args[1])
+		if err != nil || iters < 1 {
+			fmt.Println("Invalid number of iterations:", args[1])
+			return
+		}
+	}
+
+	// Analyse the sequence, by default up to Part 2's 30M iterations
+	if *analyse {
+		if iters == 0 {
+			iters = 30000000
+		}
+		if err := analyseSequence(input, iters, *every, *prefix, *out); err != nil {
+			fmt.Println("Error:", err)
+		}
+		return
+	}
+
+	// If number of iterations given, just report that
+	if iters > 0 {
+		ans, err := part2Array(input, iters)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Printf("%d iterations: %d\n", iters, ans)
+		return
+	}
+
+	// Part 1: 2020th iteration (639 for my puzzle input)
+	ans, _ := part2Array(input, 2020)
+	fmt.Println("Part 1 (2020 iterations):", ans)
+
+	// Part 2: same, but 30M iterations (unfeasible if you use simple
+	// list to keep track of history, had to change to dictionary, then
+	// to a fixed-size array)
+	ans, _ = part2Array(input, 30000000)
+	fmt.Println("Part 2 (30M iterations):", ans)
+}
+
+// Part 1: simple memory game, too slow for Part 2
+func part1(input []int, iters int) int {
+
+	// History of numbers already recited, in one long list
+	history := []int{}
+
+	// Execute each turn
+	for turn := 1; turn <= iters; turn++ {
+
+		if turn%10000 == 0 {
+			fmt.Println("Iteration", turn)
+		}
+
+		// First few turns read from list of numbers
+		if turn <= len(input) {
+			n := input[turn-1]
+			history = append(history, n)
+			//fmt.Printf("Turn %d: %d\n", turn, n)
+			continue
+		}
+
+		// Find out when the last number spoken was previously spoken
+		last := history[len(history)-1] // The last  number spoken
+		lastSpoken := -1                // turn number when it was last spoken
+		for i := len(history) - 1; i > 0; i-- {
+			if history[i-1] == last {
+				lastSpoken = i
+				break
+			}
+		}
+
+		// If that was the first time that number was spoken, say 0;
+		// Otherwise, announce the number of turns since previously spoken
+		if lastSpoken == -1 {
+			n := 0
+			//fmt.Printf("Turn %d: %d\n", turn, n)
+			history = append(history, n)
+		} else {
+			n := turn - lastSpoken - 1
+			//fmt.Printf("Turn %d: %d\n", turn, n)
+			history = append(history, n)
+		}
+	}
+
+	// Return the last value
+	//fmt.Println("Last value:", history[len(history)-1])
+	return history[len(history)-1]
+}
+
+// Part 2: same, but capable of more iterations by using dictionary instead of list
+func part2(input []int, iters int) int {
+
+	// History of numbers already recited: for each number, a list of the
+	// turns in which it was recited
+	history2 := map[int][]int{}
+	var last int // the last number spoken
+
+	// Execute each turn
+	for turn := 1; turn <= iters; turn++ {
+
+		// First few turns read from list of numbers
+		if turn <= len(input) {
+			last = input[turn-1]
+			history2[last] = append(history2[last], turn)
+			continue
+		}
+
+		// Find out when the last number spoken was previously spoken
+		hist, ok := history2[last]
+		lastSpoken := -1
+		if ok && len(hist) > 1 {
+			lastSpoken = hist[len(hist)-2] // not the last turn but the one before that
+		}
+
+		// If that was the first time that number was spoken, say 0;
+		// Otherwise, announce the number of turns since previously spoken
+		if lastSpoken == -1 {
+			last = 0
+		} else {
+			last = turn - lastSpoken - 1
+		}
+
+		// Add this turn to the history for this number
+		history2[last] = append(history2[last], turn)
+	}
+
+	// Return the last value
+	return last
+}
+
+// Part 2 again, but keeping only the turn each number was last spoken, in a
+// preallocated array instead of a dictionary of lists, which is much faster
+// and uses far less memory. The number of iterations must be from 1 to
+// maxTurns.
+func part2Array(input []int, iters int) (int, error) {
+	g, err := newGame(input, iters)
+	if err != nil {
+		return 0, err
+	}
+	for g.turn < iters {
+		g.Next()
+	}
+	return g.last, nil
+}
+
+// Most turns a game can play, since turns are kept as int32 to save memory
+const maxTurns = math.MaxInt32
+
+// Memory game engine, which keeps only the turn in which each number was
+// last spoken, so each turn takes constant time
+type Game struct {
+	input    []int   // starting numbers
+	lastTurn []int32 // turn in which each number was last spoken (0 = never)
+	turn     int     // number of turns played so far
+	last     int     // the last number spoken
+}
+
+// Create a game with the given starting numbers, able to play up to the given
+// number of turns. Numbers spoken are always less than the number of turns,
+// except for the starting numbers, which could be larger. The number of
+// turns must be from 1 to maxTurns.
+func newGame(input []int, iters int) (*Game, error) {
+	if iters < 1 || iters > maxTurns {
+		return nil, fmt.Errorf("invalid number of turns %d, must be from 1 to %d", iters, maxTurns)
+	}
+	size := iters
+	for _, n := range input {
+		if n >= size {
+			size = n + 1
+		}
+	}
+	return &Game{input: input, lastTurn: make([]int32, size)}, nil
+}
+
+// Play the next turn, and return the number spoken: first few turns read from
+// list of numbers, then if the last number was not spoken before the previous
+// turn, say 0, otherwise the number of turns since then
+func (g *Game) Next() int {
+	g.turn++
+	n := 0
+	if g.turn <= len(g.input) {
+		n = g.input[g.turn-1]
+	} else if prev := g.lastTurn[g.last]; prev != 0 {
+		n = g.turn - 1 - int(prev)
+	}
+
+	// Record when the previous number was spoken, now that it has been used
+	if g.turn > 1 {
+		g.lastTurn[g.last] = int32(g.turn - 1)
+	}
+	g.last = n
+	return n
+}
@@ -0,0 +1,176 @@
+// These are unit tests and benchmarks for Day 15
+
+package day15
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/testutil"
+)
+
+// Examples from problem definition
+var samples = [][]int{[]int{0, 3, 6}, []int{1, 3, 2}, []int{2, 1, 3},
+	[]int{1, 2, 3}, []int{2, 3, 1}, []int{3, 2, 1}, []int{3, 1, 2}}
+
+// My puzzle input
+var input = []int{11, 18, 0, 20, 1, 7, 16}
+
+// Array version should give the expected answers for 2020 iterations
+func TestPart1(t *testing.T) {
+	ans := []int{436, 1, 10, 27, 78, 438, 1836}
+	for i, s := range samples {
+		res, _ := part2Array(s, 2020)
+		if res != ans[i] {
+			t.Error("Error processing Part 1")
+			fmt.Println("Input =", s)
+			fmt.Printf("Expected %d, got %d\n", ans[i], res)
+		}
+	}
+}
+
+// All three versions should agree, including on turns within the starting
+// numbers
+func TestVersionsAgree(t *testing.T) {
+	for _, iters := range []int{1, 3, 7, 10, 2020} {
+		for _, s := range append(samples, input) {
+			a := part1(s, iters)
+			b := part2(s, iters)
+			c, _ := part2Array(s, iters)
+			if a != b || a != c {
+				t.Error("Versions disagree")
+				fmt.Println("Input =", s, "iterations =", iters)
+				fmt.Println("Got", a, b, c)
+			}
+		}
+	}
+}
+
+// Array version should give the expected answer for 30M iterations
+func TestPart2(t *testing.T) {
+	res, _ := part2Array([]int{0, 3, 6}, 30000000)
+	if res != 175594 {
+		t.Error("Error processing Part 2")
+		fmt.Printf("Expected %d, got %d\n", 175594, res)
+	}
+}
+
+// The game should speak the starting numbers, then the gaps, as in the
+// example from the problem description
+func TestGameNext(t *testing.T) {
+	for _, tc := range []struct {
+		input, want []int
+	}{
+		{[]int{0, 3, 6}, []int{0, 3, 6, 0, 3, 3, 1, 0, 4, 0}},
+		{[]int{1}, []int{1, 0, 0, 1, 3, 0, 3, 2, 0, 3}},
+		{[]int{5, 5}, []int{5, 5, 1, 0, 0, 1, 3, 0, 3, 2}},
+		{[]int{100, 0}, []int{100, 0, 0, 1, 0, 2, 0, 2, 2, 1}},
+	} {
+		g, _ := newGame(tc.input, len(tc.want))
+		got := []int{}
+		for range tc.want {
+			got = append(got, g.Next())
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Error("Starting with", tc.input, "should be", tc.want, "not", got)
+		}
+	}
+}
+
+// Longest proper prefix that is also a suffix, at each position
+func TestFailureFunction(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, want []int
+	}{
+		{[]int{}, []int{}},
+		{[]int{7}, []int{0}},
+		{[]int{1, 2, 3, 4}, []int{0, 0, 0, 0}},
+		{[]int{1, 1, 1, 1}, []int{0, 1, 2, 3}},
+		{[]int{1, 2, 1, 2, 1}, []int{0, 0, 1, 2, 3}},
+		{[]int{1, 2, 1, 1, 2, 1, 2}, []int{0, 0, 1, 1, 2, 3, 2}},
+		{[]int{0, 3, 6, 0, 3, 3, 1, 0, 4, 0}, []int{0, 0, 0, 1, 2, 0, 0, 1, 0, 1}},
+	} {
+		if got := failureFunction(tc.pattern); !reflect.DeepEqual(got, tc.want) {
+			t.Error("Failure function of", tc.pattern, "should be", tc.want, "not", got)
+		}
+	}
+}
+
+// Analysis should find the repeats of the prefix, write the sequence, and
+// reject turns between reports or a prefix less than 1
+func TestAnalyseSequence(t *testing.T) {
+	fname := t.TempDir() + "/seq.txt"
+	var err error
+	out := testutil.Capture(func() {
+		err = analyseSequence([]int{0, 3, 6}, 10, 5, 2, fname)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Looking for repeats of prefix [0 3]",
+		"Turn 5: 3 distinct", "Turn 10: 5 distinct",
+		"Prefix of length 2 repeats 1 times, first at turn 4"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output should contain %q:\n%s", want, out)
+		}
+	}
+	if data, _ := os.ReadFile(fname); string(data) != "0\n3\n6\n0\n3\n3\n1\n0\n4\n0\n" {
+		t.Errorf("Wrong sequence written:\n%s", data)
+	}
+
+	for _, tc := range []struct {
+		every, prefixLen int
+	}{
+		{0, 10}, {-1, 10}, {1000, 0}, {1000, -3},
+	} {
+		fname := t.TempDir() + "/bad.txt"
+		err := analyseSequence([]int{0, 3, 6}, 10, tc.every, tc.prefixLen, fname)
+		if err == nil {
+			t.Errorf("Every %d, prefix %d should be an error", tc.every, tc.prefixLen)
+		}
+		if _, err := os.Stat(fname); err == nil {
+			t.Error("Output file should not be created for invalid options")
+		}
+	}
+}
+
+// Numbers of turns the array can't hold should be errors, not panics or
+// wrong answers
+func TestPart2ArrayLimits(t *testing.T) {
+	for _, iters := range []int{0, -1, maxTurns + 1, math.MaxInt} {
+		if ans, err := part2Array([]int{0, 3, 6}, iters); err == nil {
+			t.Error(iters, "iterations should be an error, not", ans)
+		}
+		if err := analyseSequence([]int{0, 3, 6}, iters, 1000, 10, ""); err == nil {
+			t.Error(iters, "iterations should be an error when analysing")
+		}
+	}
+	if ans, err := part2Array([]int{0, 3, 6}, 1); err != nil || ans != 0 {
+		t.Error("1 iteration should be 0, not", ans, err)
+	}
+}
+
+// Part 1 on the puzzle input, as the program runs it
+func BenchmarkPart1(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		part2Array(input, 2020)
+	}
+}
+
+// Part 2 with dictionary of lists
+func BenchmarkPart2Map(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		part2(input, 30000000)
+	}
+}
+
+// Part 2 with fixed-size array, as the program runs it
+func BenchmarkPart2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		part2Array(input, 30000000)
+	}
+}
@@ -0,0 +1,382 @@
+// Advent of Code 2020, Day 16
+//
+// Read a file containing train ticket field names, and data for my ticket
+// and a bunch of other tickets. In Part 1, identify and remove tickets that
+// are invalid, because they do not match the allowed ranges for any field.
+// In Part 2, infer which columns relate to which fields, and report the
+// value of "departure" fields for my ticket.
+//
+// AK, 16/10/2022
+
+package day16
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Information about a field
+type Field struct {
+	Name         string
+	Ranges       []Range // from input, sorted and merged
+	PossibleCols []int   // inferred in part 2
+	Col          int     // assigned in part 2
+}
+
+// A range of valid values, inclusive
+type Range struct {
+	Min, Max int
+}
+
+// A value on a ticket that is not valid for any field, with the closest
+// range it missed
+type InvalidValue struct {
+	Ticket, Pos int    // ticket number and position on ticket (from 0)
+	Value       int    // the value
+	Field       string // field with the closest range
+	Range       Range  // the closest range
+	Dist        int    // how far the value is outside that range
+}
+
+// The puzzle input: field rules, then my ticket and nearby tickets, each
+// section after its header
+type Document struct {
+	Fields        []Field
+	YourTicket    []int
+	NearbyTickets [][]int
+}
+
+func Main(args []string) {
+
+	// Command line options
+	fs := flag.NewFlagSet("day16", flag.ExitOnError)
+	fname := fs.String("input", "input.txt", "input file")
+	report := fs.Bool("report", false, "list each invalid value on each ticket")
+	prefix := fs.String("prefix", "departure", "multiply fields starting with this in Part 2")
+	explain := fs.Bool("explain", false, "explain how columns are assigned to fields in Part 2")
+	jsonFile := fs.String("json", "", "write the final field to column mapping to this JSON file")
+	fs.Parse(args)
+
+	// Read and parse data
+	doc, err := readData(*fname)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Index of which fields each value is valid for, so that checking a
+	// value against all fields is a single lookup
+	ix := newFieldIndex(doc.Fields)
+
+	// Part 1: Find fields on nearby tickets that are invalid, i.e., not
+	// within any range, sum them up for part 1 (the error rate), and keep
+	// just the good tickets for part 2
+	var sumBad int
+	goodTix := [][]int{}
+	for i, t := range doc.NearbyTickets { // each ticket, numbered from 1
+		bad := findInvalid(doc.Fields, ix, i+1, t)
+		for _, b := range bad {
+			sumBad += b.Value
+			if *report {
+				fmt.Printf("Ticket %d, position %d: %d is invalid, nearest is %s %d-%d (off by %d)\n",
+					b.Ticket, b.Pos, b.Value, b.Field, b.Range.Min, b.Range.Max, b.Dist)
+			}
+		}
+		if len(bad) == 0 {
+			goodTix = append(goodTix, t)
+		}
+	}
+
+	fmt.Println("Part 1: Sum of bad fields =", sumBad)
+	fmt.Printf("%d of %d nearby tickets left\n", len(goodTix), len(doc.NearbyTickets))
+
+	// Just keep the good tickets and do Part 2
+	part2(doc, ix, goodTix, *prefix, *explain)
+
+	// Save the mapping if required
+	if *jsonFile != "" {
+		if err := writeMapping(*jsonFile, doc); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
+
+// Part 2: infer which positional field is which, based on values within range
+// I.e., each column could be field, X, Y or Z because all values are within
+// range. The multiply the values on "my ticket" for all the columns starting
+// with the given prefix, e.g., "departure". If explain is true, show how the
+// columns were assigned. Returns the answer, and false if any of the fields
+// with the prefix could not be assigned a column.
+func part2(doc *Document, ix *FieldIndex, tickets [][]int, prefix string, explain bool) (int64, bool) {
+	fields := doc.Fields
+
+	// Look at each field, and determine which columns could apply
+	fmt.Println("\nPart 2: determining possible columns for each field")
+	setPossibleColsIndexed(fields, ix, tickets, len(doc.YourTicket))
+	if explain {
+		printMatrix(fields, len(doc.YourTicket))
+	}
+
+	// Now iterate to assign columns to fields, basically using a process of
+	// elimination, since there is always a field in the list for which only
+	// one column is possible:
+	// 1. find a field with only one possible column
+	// 2. assign that field to that column
+	// 3. remove that column number from all fields
+	// 4. repeat until no remaining fields with one possible column
+	fmt.Println("Assigning columns to fields")
+	for {
+
+		// Find a field that has only one possible column
+		onePoss := -1
+		for i, f := range fields {
+			if len(f.PossibleCols) == 1 {
+				onePoss = i
+				break
+			}
+		}
+
+		// If no field with one possible column, we are done
+		if onePoss == -1 {
+			break
+		}
+
+		// Assign this field to the single possible column
+		col := fields[onePoss].PossibleCols[0]
+		fields[onePoss].Col = col
+		fmt.Printf("  %s assigned to column %d\n", fields[onePoss].Name, col)
+
+		// Remove that column number from all the fields (including the one just assigned)
+		removedFrom := []string{}
+		for i := 0; i < len(fields); i++ {
+			n := len(fields[i].PossibleCols)
+			fields[i].PossibleCols = removeItem(col, fields[i].PossibleCols)
+			if i != onePoss && len(fields[i].PossibleCols) < n {
+				removedFrom = append(removedFrom, fields[i].Name)
+			}
+		}
+		if explain && len(removedFrom) > 0 {
+			fmt.Printf("    column %d eliminated from %s\n", col, strings.Join(removedFrom, ", "))
+		}
+	}
+
+	// If any fields are left without a column, elimination got stuck
+	stuck := []Field{}
+	for _, f := range fields {
+		if f.Col < 0 {
+			stuck = append(stuck, f)
+		}
+	}
+	if len(stuck) > 0 {
+		fmt.Printf("Could not assign columns to %d fields\n", len(stuck))
+		if explain {
+			printStuck(stuck)
+		}
+	}
+
+	// Now that we know the column for each field, multiply the values on
+	// my ticket for all columns starting with the prefix
+	var ans int64 = 1
+	for _, f := range fields {
+		if strings.HasPrefix(f.Name, prefix) {
+			if f.Col < 0 {
+				fmt.Println("Part 2: no answer, since", f.Name, "has no column")
+				return 0, false
+			}
+			ans *= int64(doc.YourTicket[f.Col])
+		}
+	}
+	fmt.Println("Part 2 answer:", ans)
+	return ans, true
+}
+
+// Look at each field, and determine which columns could apply, i.e., all
+// tickets have a valid value for that field in that column
+func setPossibleCols(fields []Field, tickets [][]int, ncols int) {
+	for i := 0; i < len(fields); i++ { // each field
+		fields[i].PossibleCols = []int{}
+		fields[i].Col = -1 // not assigned yet
+
+		for c := 0; c < ncols; c++ { // each column
+			ok := true                  // assume valid
+			for _, t := range tickets { // Check each ticket
+				if !isValueValidForField(t[c], fields[i]) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				fields[i].PossibleCols = append(fields[i].PossibleCols, c)
+			}
+		}
+		//fmt.Println(fields[i])
+	}
+}
+
+// Remove item from a list
+func removeItem(n int, lst []int) []int {
+	lst2 := []int{}
+	for i := 0; i < len(lst); i++ {
+		if lst[i] != n {
+			lst2 = append(lst2, lst[i])
+		}
+	}
+	return lst2
+}
+
+// Find the values on a ticket that are not valid for any field (using the
+// index), with the nearest range each one missed
+func findInvalid(fields []Field, ix *FieldIndex, ticket int, t []int) []InvalidValue {
+	bad := []InvalidValue{}
+	for pos, n := range t { // each number on ticket
+		if ix.Valid(n) {
+			continue
+		}
+		nearest := InvalidValue{Ticket: ticket, Pos: pos, Value: n, Dist: -1}
+		for _, f := range fields {
+			for _, r := range f.Ranges {
+				d := r.Min - n
+				if n > r.Max {
+					d = n - r.Max
+				}
+				if nearest.Dist < 0 || d < nearest.Dist {
+					nearest.Field, nearest.Range, nearest.Dist = f.Name, r, d
+				}
+			}
+		}
+		bad = append(bad, nearest)
+	}
+	return bad
+}
+
+// Read and parse problem data
+func readData(filename string) (*Document, error) {
+
+	// Read input file into list of strings
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	// Parse data to find fields and tickets.
+	// Up to the first header: valid ranges for different fields
+	// E.g., class: 1-3 or 5-7
+	// After "your ticket:", my ticket as a list of numbers
+	// After "nearby tickets:", other tickets, one per line
+	doc := &Document{}
+	section := "fields"
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		if len(l) == 0 {
+			continue
+		}
+
+		// Headers start a new section, each once and in order
+		if l == "your ticket:" || l == "nearby tickets:" {
+			if !(section == "fields" && l == "your ticket:") &&
+				!(section == "your ticket:" && l == "nearby tickets:") {
+				return nil, fmt.Errorf("line %d: unexpected %q after %s", i+1, l, section)
+			}
+			section = l
+			continue
+		}
+
+		// Otherwise parse a field or ticket, depending on the section
+		if section == "fields" {
+			f, err := parseField(l)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			doc.Fields = append(doc.Fields, f)
+			continue
+		}
+		t, err := parseTicket(l)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		if len(t) != len(doc.Fields) {
+			return nil, fmt.Errorf("line %d: ticket has %d values, expected %d",
+				i+1, len(t), len(doc.Fields))
+		}
+		if section == "nearby tickets:" {
+			doc.NearbyTickets = append(doc.NearbyTickets, t)
+		} else if doc.YourTicket == nil {
+			doc.YourTicket = t
+		} else {
+			return nil, fmt.Errorf("line %d: more than one ticket under \"your ticket:\"", i+1)
+		}
+	}
+
+	// Make sure my ticket and the nearby tickets were found
+	if doc.YourTicket == nil {
+		return nil, fmt.Errorf("no \"your ticket:\" section in %s", filename)
+	}
+	if section != "nearby tickets:" {
+		return nil, fmt.Errorf("no \"nearby tickets:\" section in %s", filename)
+	}
+	return doc, nil
+}
+
+// Parse a field rule, with any number of ranges, e.g.,
+// class: 1-3 or 5-7 or 9-11
+func parseField(l string) (Field, error) {
+	name, rest, ok := strings.Cut(l, ":")
+	if !ok || len(strings.TrimSpace(name)) == 0 {
+		return Field{}, fmt.Errorf("missing field name in %q", l)
+	}
+	f := Field{Name: strings.TrimSpace(name)}
+	for _, rs := range strings.Split(rest, " or ") {
+		lo, hi, ok := strings.Cut(strings.TrimSpace(rs), "-")
+		mn, err1 := strconv.Atoi(lo)
+		mx, err2 := strconv.Atoi(hi)
+		if !ok || err1 != nil || err2 != nil || mn > mx {
+			return Field{}, fmt.Errorf("invalid range %q for field %s", strings.TrimSpace(rs), f.Name)
+		}
+		f.Ranges = append(f.Ranges, Range{mn, mx})
+	}
+	f.Ranges = mergeRanges(f.Ranges)
+	return f, nil
+}
+
+// Sort ranges and merge any that overlap or touch
+func mergeRanges(ranges []Range) []Range {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Min < ranges[j].Min })
+	merged := []Range{}
+	for _, r := range ranges {
+		last := len(merged) - 1
+		if last >= 0 && r.Min <= merged[last].Max+1 {
+			merged[last].Max = max(merged[last].Max, r.Max)
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// Is a field value valid for given field?
+func isValueValidForField(n int, f Field) bool {
+	for _, r := range f.Ranges {
+		if n >= r.Min && n <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse a comma-delimited list of numbers
+func parseTicket(s string) ([]int, error) {
+	tix := []int{}
+	for _, n := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(n))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ticket value %q", n)
+		}
+		tix = append(tix, v)
+	}
+	return tix, nil
+}
@@ -0,0 +1,433 @@
+// These are unit tests and benchmarks for Day 16
+
+package day16
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/testutil"
+)
+
+// Generate a random document with the given number of fields (and columns)
+// and nearby tickets, with values up to 1000, nearly all of them valid
+func synthetic(nfields, ntickets int, seed int64) *Document {
+	rng := rand.New(rand.NewSource(seed))
+	doc := &Document{}
+	for i := 0; i < nfields; i++ {
+		f := Field{Name: fmt.Sprintf("field%d", i)}
+		for j := 0; j < 2+rng.Intn(3); j++ {
+			lo := 1 + rng.Intn(900)
+			f.Ranges = append(f.Ranges, Range{lo, lo + rng.Intn(400)})
+		}
+		f.Ranges = mergeRanges(f.Ranges)
+		doc.Fields = append(doc.Fields, f)
+	}
+	for i := 0; i < ntickets; i++ {
+		t := []int{}
+		for j := 0; j < nfields; j++ {
+			if rng.Intn(100) == 0 { // occasional random, likely invalid value
+				t = append(t, 1+rng.Intn(1000))
+				continue
+			}
+			f := doc.Fields[rng.Intn(nfields)]
+			r := f.Ranges[rng.Intn(len(f.Ranges))]
+			t = append(t, r.Min+rng.Intn(r.Max-r.Min+1))
+		}
+		doc.NearbyTickets = append(doc.NearbyTickets, t)
+	}
+	doc.YourTicket = doc.NearbyTickets[0]
+	return doc
+}
+
+// Check a value against all fields, without the index
+func validNested(fields []Field, n int) bool {
+	for _, f := range fields {
+		if isValueValidForField(n, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// The index should agree with checking the ranges directly, for every value
+// and field, including fields past the first 64, with and without the table
+// of segments
+func TestFieldIndex(t *testing.T) {
+	for i, doc := range []*Document{synthetic(20, 10, 1), synthetic(150, 10, 2),
+		synthetic(20, 10, 3), synthetic(150, 10, 4)} {
+		ix := newFieldIndex(doc.Fields)
+		if i >= 2 {
+			ix.table = nil // search for the segment, as for very wide ranges
+		}
+		for n := -5; n <= 1500; n++ {
+			if ix.Valid(n) != validNested(doc.Fields, n) {
+				t.Error("Index disagrees for any field")
+				fmt.Println("Value =", n)
+			}
+			for i, f := range doc.Fields {
+				if ix.ValidFor(n, i) != isValueValidForField(n, f) {
+					t.Error("Index disagrees for field")
+					fmt.Println("Value =", n, "field =", f.Name)
+				}
+			}
+		}
+	}
+}
+
+// A very wide range should not make the index big or slow to build
+func TestFieldIndexWideRange(t *testing.T) {
+	fields := []Field{
+		{Name: "wide", Ranges: []Range{{1, 2000000000}}},
+		{Name: "narrow", Ranges: []Range{{5, 10}, {1999999990, 2000000005}}},
+	}
+	ix := newFieldIndex(fields)
+	if len(ix.bits) > 10 {
+		t.Error("Index should have a few segments, not", len(ix.bits))
+	}
+	for _, c := range []struct {
+		n            int
+		wide, narrow bool
+	}{
+		{0, false, false}, {1, true, false}, {5, true, true}, {10, true, true},
+		{11, true, false}, {1999999990, true, true}, {2000000000, true, true},
+		{2000000001, false, true}, {2000000006, false, false},
+	} {
+		if ix.ValidFor(c.n, 0) != c.wide || ix.ValidFor(c.n, 1) != c.narrow {
+			t.Errorf("Value %d should be valid for wide %v, narrow %v", c.n, c.wide, c.narrow)
+		}
+		if ix.Valid(c.n) != (c.wide || c.narrow) {
+			t.Errorf("Value %d should be valid %v", c.n, c.wide || c.narrow)
+		}
+	}
+}
+
+// Possible columns for each field should be the same with and without the
+// index, on the puzzle input and synthetic input
+func TestPossibleCols(t *testing.T) {
+	input, err := readData("input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []*Document{input, synthetic(100, 500, 3)} {
+		ix := newFieldIndex(doc.Fields)
+		good := validTickets(doc)
+		ncols := len(doc.YourTicket)
+		setPossibleCols(doc.Fields, good, ncols)
+		sb := append([]Field{}, doc.Fields...)
+		setPossibleColsIndexed(doc.Fields, ix, good, ncols)
+		if !reflect.DeepEqual(doc.Fields, sb) {
+			t.Error("Possible columns differ with index")
+		}
+	}
+}
+
+// Ranges should be sorted, and merged if they overlap, touch or contain one
+// another, but not if there is a gap
+func TestMergeRanges(t *testing.T) {
+	for _, tc := range []struct {
+		ranges, want []Range
+	}{
+		{[]Range{{5, 7}, {1, 3}}, []Range{{1, 3}, {5, 7}}},
+		{[]Range{{1, 5}, {3, 8}}, []Range{{1, 8}}},
+		{[]Range{{1, 3}, {4, 6}}, []Range{{1, 6}}},
+		{[]Range{{1, 10}, {3, 4}}, []Range{{1, 10}}},
+		{[]Range{{8, 9}, {1, 2}, {3, 5}, {7, 7}}, []Range{{1, 5}, {7, 9}}},
+		{[]Range{{2, 2}, {2, 2}}, []Range{{2, 2}}},
+		{[]Range{}, []Range{}},
+	} {
+		if got := mergeRanges(append([]Range{}, tc.ranges...)); !reflect.DeepEqual(got, tc.want) {
+			t.Error(tc.ranges, "should merge to", tc.want, "not", got)
+		}
+	}
+}
+
+// Field rules with any number of ranges, merged, and malformed rules should
+// be errors
+func TestParseField(t *testing.T) {
+	for l, want := range map[string]Field{
+		"class: 1-3 or 5-7":               {Name: "class", Ranges: []Range{{1, 3}, {5, 7}}},
+		"departure time: 5-9 or 1-4":      {Name: "departure time", Ranges: []Range{{1, 9}}},
+		"row: 6-11 or 33-44 or 10-35":     {Name: "row", Ranges: []Range{{6, 44}}},
+		"seat: 13-40":                     {Name: "seat", Ranges: []Range{{13, 40}}},
+		"zone: 1-2 or 4-5 or 7-8 or 3-3 ": {Name: "zone", Ranges: []Range{{1, 5}, {7, 8}}},
+	} {
+		f, err := parseField(l)
+		if err != nil || !reflect.DeepEqual(f, want) {
+			t.Errorf("%q should be %v, not %v (%v)", l, want, f, err)
+		}
+	}
+	for _, bad := range []string{"class 1-3", ": 1-3", "class: 3-1", "class: 1-3 or",
+		"class: 1-3 or 5", "class: a-b", "class: 1-3, 5-7", "class:"} {
+		if f, err := parseField(bad); err == nil {
+			t.Errorf("%q should be an error, not %v", bad, f)
+		}
+	}
+}
+
+// Write a document to a temporary file and read it
+func readString(t *testing.T, data string) (*Document, error) {
+	fname := t.TempDir() + "/input.txt"
+	if err := os.WriteFile(fname, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return readData(fname)
+}
+
+// My ticket and nearby tickets should come from their own sections, and
+// missing, repeated or out of order sections should be errors
+func TestReadDataSections(t *testing.T) {
+	doc, err := readString(t, "a: 1-3\nb: 4-6\n\nyour ticket:\n1,4\n\nnearby tickets:\n2,5\n3,9\n")
+	if err != nil || len(doc.Fields) != 2 || !reflect.DeepEqual(doc.YourTicket, []int{1, 4}) ||
+		!reflect.DeepEqual(doc.NearbyTickets, [][]int{{2, 5}, {3, 9}}) {
+		t.Error("Document is wrong:", doc, err)
+	}
+	doc, err = readString(t, "a: 1-3\nyour ticket:\n1\nnearby tickets:\n")
+	if err != nil || len(doc.NearbyTickets) != 0 {
+		t.Error("No nearby tickets should be allowed:", doc, err)
+	}
+	for _, tc := range []struct {
+		data, want string
+	}{
+		{"a: 1-3\nnearby tickets:\n2\n", "line 2: unexpected \"nearby tickets:\" after fields"},
+		{"a: 1-3\nyour ticket:\nnearby tickets:\n2\n", "no \"your ticket:\" section"},
+		{"a: 1-3\nyour ticket:\n1\n", "no \"nearby tickets:\" section"},
+		{"a: 1-3\n2\n", "line 2: missing field name"},
+		{"a: 1-3\nnearby tickets:\n2\nyour ticket:\n1\n", "line 2: unexpected"},
+		{"a: 1-3\nyour ticket:\n1\nyour ticket:\n1\n", "line 4: unexpected \"your ticket:\" after your ticket:"},
+		{"a: 1-3\nyour ticket:\n1\nnearby tickets:\n2\nnearby tickets:\n", "line 6: unexpected"},
+		{"a: 1-3\nyour ticket:\n1\n2\nnearby tickets:\n", "line 4: more than one ticket"},
+		{"a: 1-3\nyour ticket:\n1,2\nnearby tickets:\n", "line 3: ticket has 2 values"},
+		{"a: 1-3\nyour ticket:\n1\nnearby tickets:\nb: 1-2\n", "line 5: invalid ticket value"},
+	} {
+		_, err := readString(t, tc.data)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q should give %q, not %v", tc.data, tc.want, err)
+		}
+	}
+}
+
+// Part 2 example from the problem description
+const part2Sample = `class: 0-1 or 4-19
+row: 0-5 or 8-19
+seat: 0-13 or 16-19
+
+your ticket:
+11,12,13
+
+nearby tickets:
+3,9,18
+15,1,5
+5,14,9
+`
+
+// Part 2 example from the problem description: the answer is the product of
+// the values on my ticket of the fields starting with the prefix
+func TestPart2Prefix(t *testing.T) {
+	for prefix, want := range map[string]int64{
+		"class": 12,
+		"row":   11,
+		"s":     13,
+		"":      11 * 12 * 13,
+		"none":  1,
+	} {
+		doc, err := readString(t, part2Sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ans int64
+		var ok bool
+		testutil.Quiet(func() {
+			ans, ok = part2(doc, newFieldIndex(doc.Fields), validTickets(doc), prefix, false)
+		})
+		if !ok || ans != want {
+			t.Errorf("Prefix %q should give %d, not %d", prefix, want, ans)
+		}
+	}
+}
+
+// Explaining the Part 2 example should show the possible columns for each
+// field, then each assignment and the columns it eliminates, and the JSON
+// mapping should be in column order with the values on my ticket
+func TestExplainSample(t *testing.T) {
+	doc, err := readString(t, part2Sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := testutil.Capture(func() {
+		part2(doc, newFieldIndex(doc.Fields), validTickets(doc), "", true)
+	})
+	matrix := `       0  1  2
+class  .  X  X  (2 possible)
+row    X  X  X  (3 possible)
+seat   .  .  X  (1 possible)
+`
+	steps := `  seat assigned to column 2
+    column 2 eliminated from class, row
+  class assigned to column 1
+    column 1 eliminated from row
+  row assigned to column 0
+`
+	for _, want := range []string{matrix, steps} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output should contain:\n%s\nnot:\n%s", want, out)
+		}
+	}
+
+	fname := t.TempDir() + "/mapping.json"
+	if err := writeMapping(fname, doc); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(fname)
+	var mapping []Mapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		field      string
+		col, value int
+	}{{"row", 0, 11}, {"class", 1, 12}, {"seat", 2, 13}}
+	if len(mapping) != len(want) {
+		t.Fatalf("Mapping should have %d fields:\n%s", len(want), data)
+	}
+	for i, m := range mapping {
+		w := want[i]
+		if m.Field != w.field || m.Column == nil || *m.Column != w.col || m.Value == nil || *m.Value != w.value {
+			t.Errorf("Mapping %d should be %s in column %d with %d:\n%s", i, w.field, w.col, w.value, data)
+		}
+	}
+}
+
+// When elimination gets stuck, the fields left should have no column, be
+// explained, and be written to JSON with no column or value, after the
+// fields that were assigned
+func TestUnsolvable(t *testing.T) {
+	doc, err := readString(t, "a: 1-5\nb: 1-5\nc: 10-20\n\nyour ticket:\n1,2,15\n\nnearby tickets:\n3,4,12\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ok bool
+	out := testutil.Capture(func() {
+		_, ok = part2(doc, newFieldIndex(doc.Fields), validTickets(doc), "a", true)
+	})
+	if ok || doc.Fields[0].Col != -1 || doc.Fields[1].Col != -1 || doc.Fields[2].Col != 2 {
+		t.Error("Only c should be assigned, not", doc.Fields)
+	}
+	for _, want := range []string{"Could not assign columns to 2 fields",
+		"  a: [0 1]\n  b: [0 1]\n", "Part 2: no answer, since a has no column"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output should contain %q:\n%s", want, out)
+		}
+	}
+
+	fname := t.TempDir() + "/mapping.json"
+	if err := writeMapping(fname, doc); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(fname)
+	var mapping []map[string]any
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]any{
+		{"field": "c", "column": 2.0, "value": 15.0},
+		{"field": "a", "column": nil, "value": nil},
+		{"field": "b", "column": nil, "value": nil},
+	}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Errorf("Mapping should be %v, not %v", want, mapping)
+	}
+}
+
+// Synthetic input for benchmarks: 20 fields, 5000 tickets, and just the
+// valid tickets for part 2
+var benchDoc = synthetic(20, 5000, 42)
+var benchGood = validTickets(benchDoc)
+
+// Nearby tickets with no invalid values
+func validTickets(doc *Document) [][]int {
+	ix := newFieldIndex(doc.Fields)
+	good := [][]int{}
+	for i, tk := range doc.NearbyTickets {
+		if len(findInvalid(doc.Fields, ix, i+1, tk)) == 0 {
+			good = append(good, tk)
+		}
+	}
+	return good
+}
+
+// Part 1 validity check, each value against each field
+func BenchmarkValidNested(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, t := range benchDoc.NearbyTickets {
+			for _, n := range t {
+				validNested(benchDoc.Fields, n)
+			}
+		}
+	}
+}
+
+// Part 1 validity check, with the index (including building it)
+func BenchmarkValidIndexed(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ix := newFieldIndex(benchDoc.Fields)
+		for _, t := range benchDoc.NearbyTickets {
+			for _, n := range t {
+				ix.Valid(n)
+			}
+		}
+	}
+}
+
+// Part 2 possible columns, each field against each column of each ticket
+func BenchmarkPossibleColsNested(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		setPossibleCols(benchDoc.Fields, benchGood, 20)
+	}
+}
+
+// Part 2 possible columns, with the index (already built for part 1)
+func BenchmarkPossibleColsIndexed(b *testing.B) {
+	ix := newFieldIndex(benchDoc.Fields)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		setPossibleColsIndexed(benchDoc.Fields, ix, benchGood, 20)
+	}
+}
+
+// Part 1 on the puzzle input, including building the index
+func BenchmarkPart1(b *testing.B) {
+	doc, err := readData("input.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		ix := newFieldIndex(doc.Fields)
+		sumBad := 0
+		for j, t := range doc.NearbyTickets {
+			for _, bad := range findInvalid(doc.Fields, ix, j+1, t) {
+				sumBad += bad.Value
+			}
+		}
+	}
+}
+
+// Part 2 on the puzzle input, using the valid tickets from Part 1
+func BenchmarkPart2(b *testing.B) {
+	doc, err := readData("input.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	ix := newFieldIndex(doc.Fields)
+	good := validTickets(doc)
+	testutil.Quiet(func() {
+		for i := 0; i < b.N; i++ {
+			part2(doc, ix, good, "departure", false)
+		}
+	})
+}
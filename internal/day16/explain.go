@@ -0,0 +1,89 @@
+// Explanation of how day 16 Part 2 assigns columns to fields, and JSON
+// output of the final mapping
+
+package day16
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Print which columns are possible for each field, before any elimination,
+// as a matrix with one row per field and X for each possible column
+func printMatrix(fields []Field, ncols int) {
+
+	// Width of field names
+	w := 0
+	for _, f := range fields {
+		w = max(w, len(f.Name))
+	}
+
+	// Column numbers, then one row per field
+	fmt.Printf("%*s", w, "")
+	for c := 0; c < ncols; c++ {
+		fmt.Printf(" %2d", c)
+	}
+	fmt.Println()
+	for _, f := range fields {
+		fmt.Printf("%-*s", w, f.Name)
+		for c := 0; c < ncols; c++ {
+			if isIn(c, f.PossibleCols) {
+				fmt.Print("  X")
+			} else {
+				fmt.Print("  .")
+			}
+		}
+		fmt.Printf("  (%d possible)\n", len(f.PossibleCols))
+	}
+}
+
+// Print the fields that could not be assigned, and the columns each could
+// still take
+func printStuck(fields []Field) {
+	fmt.Println("Remaining ambiguous fields and candidate columns:")
+	for _, f := range fields {
+		fmt.Printf("  %s: %v\n", f.Name, f.PossibleCols)
+	}
+}
+
+// Column assigned to a field, and value on my ticket, as written to JSON
+type Mapping struct {
+	Field  string `json:"field"`
+	Column *int   `json:"column"` // null if not assigned
+	Value  *int   `json:"value"`  // value on my ticket, null if not assigned
+}
+
+// Write the field to column mapping as JSON, in column order, with
+// unassigned fields at the end
+func writeMapping(filename string, doc *Document) error {
+	mapping := []Mapping{}
+	for _, f := range doc.Fields {
+		m := Mapping{Field: f.Name}
+		if f.Col >= 0 {
+			col, val := f.Col, doc.YourTicket[f.Col]
+			m.Column, m.Value = &col, &val
+		}
+		mapping = append(mapping, m)
+	}
+	sort.SliceStable(mapping, func(i, j int) bool {
+		a, b := mapping[i].Column, mapping[j].Column
+		return a != nil && (b == nil || *a < *b)
+	})
+	data, err := json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// Is a number in a list?
+func isIn(n int, lst []int) bool {
+	for _, x := range lst {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+// Fast lookup of which fields a value is valid for, for day 16. The start
+// and end of every range split the values into segments, within which every
+// value is valid for the same fields. Each segment has a bitset with one
+// bit per field, so checking a value against all fields is a binary search
+// for its segment, and the fields possible for a column are found by ANDing
+// the bitsets of the values in that column. The size of the index depends
+// on the number of ranges, not how wide they are, except that if the ranges
+// cover few enough values, a table gives the segment of each value directly
+// instead of searching.
+
+package day16
+
+import (
+	"slices"
+	"sort"
+)
+
+// Most values covered by the table of segments for each value
+const maxTable = 1 << 20
+
+// Bitset of valid fields for each segment of values between range ends
+type FieldIndex struct {
+	starts []int    // first value of each segment, in increasing order
+	words  int      // number of 64-bit words per segment
+	bits   []uint64 // bitsets for each segment, one after the other
+	table  []int32  // segment of each value from starts[0], if not too many
+}
+
+// Build the index for a list of fields
+func newFieldIndex(fields []Field) *FieldIndex {
+
+	// Each range starts a segment at its first value, and another after
+	// its last value
+	ix := &FieldIndex{words: (len(fields) + 63) / 64}
+	for _, f := range fields {
+		for _, r := range f.Ranges {
+			ix.starts = append(ix.starts, r.Min, r.Max+1)
+		}
+	}
+	sort.Ints(ix.starts)
+	ix.starts = slices.Compact(ix.starts)
+
+	// Set the bit for each field in each segment of its ranges
+	ix.bits = make([]uint64, len(ix.starts)*ix.words)
+	for i, f := range fields {
+		for _, r := range f.Ranges {
+			k, _ := slices.BinarySearch(ix.starts, r.Min)
+			for ; k < len(ix.starts) && ix.starts[k] <= r.Max; k++ {
+				ix.bits[k*ix.words+i/64] |= 1 << (i % 64)
+			}
+		}
+	}
+
+	// Table of the segment for each value up to the start of the last
+	// segment, if the ranges are narrow enough
+	if n := len(ix.starts); n > 0 && ix.starts[n-1]-ix.starts[0] <= maxTable {
+		ix.table = make([]int32, ix.starts[n-1]-ix.starts[0])
+		for k := range n - 1 {
+			for v := ix.starts[k]; v < ix.starts[k+1]; v++ {
+				ix.table[v-ix.starts[0]] = int32(k)
+			}
+		}
+	}
+	return ix
+}
+
+// Bitset of fields a value is valid for, nil if it is before every range
+func (ix *FieldIndex) lookup(n int) []uint64 {
+	if ix.table != nil {
+		if n < ix.starts[0] {
+			return nil
+		}
+		k := len(ix.starts) - 1 // after the last range
+		if i := n - ix.starts[0]; i < len(ix.table) {
+			k = int(ix.table[i])
+		}
+		return ix.bits[k*ix.words : (k+1)*ix.words]
+	}
+	k, found := slices.BinarySearch(ix.starts, n)
+	if !found {
+		k-- // segment starting before n
+	}
+	if k < 0 {
+		return nil
+	}
+	return ix.bits[k*ix.words : (k+1)*ix.words]
+}
+
+// Is a value valid for any field?
+func (ix *FieldIndex) Valid(n int) bool {
+	for _, w := range ix.lookup(n) {
+		if w != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Is a value valid for the field with the given index?
+func (ix *FieldIndex) ValidFor(n, field int) bool {
+	b := ix.lookup(n)
+	return b != nil && b[field/64]&(1<<(field%64)) != 0
+}
+
+// Same as setPossibleCols(), but using the index: for each column, AND the
+// bitsets of the values in that column across all tickets, leaving the
+// fields that all the values are valid for
+func setPossibleColsIndexed(fields []Field, ix *FieldIndex, tickets [][]int, ncols int) {
+	for i := range fields {
+		fields[i].PossibleCols = []int{}
+		fields[i].Col = -1 // not assigned yet
+	}
+	possible := make([]uint64, ix.words)
+	for c := 0; c < ncols; c++ { // each column
+
+		// Start with all fields possible
+		for w := range possible {
+			possible[w] = ^uint64(0)
+		}
+
+		// Narrow down using the value in this column of each ticket
+		for _, t := range tickets {
+			b := ix.lookup(t[c])
+			if b == nil {
+				clear(possible)
+				break
+			}
+			found := false
+			for w := range possible {
+				possible[w] &= b[w]
+				found = found || possible[w] != 0
+			}
+			if !found { // no field possible, no need to look further
+				break
+			}
+		}
+
+		// Add this column to each field still possible
+		for i := range fields {
+			if possible[i/64]&(1<<(i%64)) != 0 {
+				fields[i].PossibleCols = append(fields[i].PossibleCols, c)
+			}
+		}
+	}
+}
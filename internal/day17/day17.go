@@ -0,0 +1,331 @@
+// Advent of Code 2020, Day 17
+//
+// Input is a set of "cubes" in 2-d space, either on or off. For part 1,
+// this is extended to 3-d space, for part 2 4-d space. Simulate a set of
+// simple rules, depending on current state of a cube and the number of "on"
+// neighbours it has. Simulation is supposed to occur "simulataneously", so
+// apply changes to future state, then roll them to current state after each
+// iteration. Part 2 is a trivial set of modifications to Part 1, to make it
+// 4-d instead of 3-d, so only the Part 2 code is shown here.
+//
+// AK, 17/10/2022
+
+package day17
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/anim"
+	"github.com/andreaskaempf/adventofcode2020/internal/cycle"
+	"github.com/andreaskaempf/adventofcode2020/internal/parallel"
+)
+
+// One point in 4-d space (use 3-d for part 1)
+type Point struct {
+	x, y, z, h int // can be negative
+}
+
+// The current and next state of each known point (unknown is off)
+var current map[Point]int
+var next map[Point]int
+
+func Main(args []string) {
+
+	// Input file, and number of iterations (6 for the puzzle)
+	fs := flag.NewFlagSet("day17", flag.ExitOnError)
+	fname := fs.String("input", "input.txt", "input file")
+	maxIters := fs.Int("max", 6, "number of iterations")
+	workers := fs.Int("workers", 1, "number of goroutines to share the work of each iteration")
+	play := fs.Bool("play", false, "animate a z/w slice in the terminal")
+	fps := fs.Float64("fps", 2, "frames per second when animating")
+	sliceZ := fs.Int("z", 0, "z coordinate of slice to animate")
+	sliceH := fs.Int("w", 0, "w coordinate of slice to animate")
+	gifFile := fs.String("gif", "", "write the z/w slice after each iteration to an animated GIF")
+	cell := fs.Int("cell", 8, "size of each cube in the GIF, in pixels")
+	colours := fs.String("colours", "000000,303030,40ff40",
+		"GIF colours as RRGGBB: background, inactive cube, active cube")
+	fs.Parse(args)
+
+	// Check GIF options before doing any work
+	var gw *anim.GIFWriter
+	if *gifFile != "" {
+		var err error
+		gw, err = anim.NewGIFWriter(".#", *colours, *cell, 1, *fps)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+
+	// Read data set and convert to a set of points
+	data, _ := ioutil.ReadFile(*fname)
+	readState(data)
+
+	// Animate the chosen slice if required, starting with initial state
+	var player *anim.Player
+	if *play {
+		player = anim.NewPlayer(*fps)
+		defer player.Close()
+	}
+	show := func(iter int) bool {
+		rows, left, top := renderSlice(*sliceZ, *sliceH)
+		if gw != nil {
+			gw.Add(rows, left, top)
+		}
+		if player == nil {
+			return true
+		}
+		title := fmt.Sprintf("Iteration %d, z=%d, w=%d (%d active cubes in total)",
+			iter, *sliceZ, *sliceH, countActive())
+		return player.Frame(title, rows)
+	}
+	show(0)
+
+	// Run each iteration, stopping early if the state repeats
+	cycles := cycle.NewDetector()
+	cycles.Check(activeState(), 0)
+	iters := *maxIters
+	for iter := 1; iter <= iters; iter++ {
+
+		// Look at each cube in current space, including 1 past current edge
+		if player == nil {
+			fmt.Println("Iteration", iter)
+		}
+		step(*workers)
+
+		// After each iteration, roll over the next states back to the current
+		rollOver()
+		if !show(iter) {
+			break
+		}
+
+		// If this state has been seen before, the rest of the iterations
+		// just go round the same cycle, so only the last part of a lap
+		// needs to be simulated
+		if outcome, ok := cycles.Check(activeState(), iter); ok {
+			if player == nil {
+				fmt.Println("Simulation", outcome)
+			}
+			iters = iter + (iters-iter)%outcome.Period
+			cycles = cycle.NewDetector()
+		}
+	}
+
+	// Count the number of active cubes
+	// For Part 1, sample should be 112 after 6 iterations, input 336
+	// For Part 2, 848 and 2620
+	if player != nil {
+		player.Close()
+	}
+	fmt.Printf("Part 2: %d active cubes\n", countActive())
+
+	// Write the GIF, if required
+	if gw != nil {
+		if err := gw.Save(*gifFile); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
+
+// Initialize global state maps, and set the cubes in the input (a 2-d
+// slice, with # for active cubes) as the current state
+func readState(data []byte) {
+	current = map[Point]int{}
+	next = map[Point]int{}
+	var x, y, z, h int // z and h are zero in input
+	for _, b := range data {
+		if b == '\n' { // start next row
+			y++
+			x = 0
+		} else if b == '#' { // hash means on
+			setCurrentState(x, y, z, h, 1)
+			x++
+		} else if b == '.' { // period means off
+			//setCurrentState(x, y, 0, 0) // not really necessary
+			x++
+		} else {
+			fmt.Println("Unknown character, ignoring:", b)
+			x++
+		}
+	}
+}
+
+// Work out the next state of every cube in the current space, including 1
+// past the current edge, with the x coordinates shared between the given
+// number of goroutines (see internal/parallel)
+func step(workers int) {
+	lo, hi := getDims()
+	changes := make([]map[Point]int, max(workers, 1)) // changes found by each band
+	parallel.Bands(hi.x-lo.x+3, workers, func(band, x0, x1 int) {
+		changes[band] = stepRange(lo, hi, lo.x-1+x0, lo.x-1+x1)
+	})
+	for _, c := range changes {
+		for p, st := range c {
+			setNextState(p.x, p.y, p.z, p.h, st)
+		}
+	}
+}
+
+// Work out the next state of the cubes with x coordinates from x0 up to (but
+// not including) x1, within the given dimensions plus 1 past each edge, and
+// return the changes, without changing any state
+func stepRange(min, max Point, x0, x1 int) map[Point]int {
+	changes := map[Point]int{}
+	for x := x0; x < x1; x++ {
+		for y := min.y - 1; y <= max.y+1; y++ {
+			for z := min.z - 1; z <= max.z+1; z++ {
+				for h := min.h - 1; h <= max.h+1; h++ {
+
+					// Get current state and number of active neighbors
+					state := getCurrentState(x, y, z, h)
+					nactive := activeNeighbours(x, y, z, h)
+
+					// If a cube is active and exactly 2 or 3 of its neighbors
+					// are also active, the cube remains active. Otherwise, the
+					// cube becomes inactive.
+					if state == 1 {
+						if !(nactive == 2 || nactive == 3) {
+							changes[Point{x, y, z, h}] = 0
+						}
+					}
+
+					// If a cube is inactive but exactly 3 of its neighbors
+					// are active, the cube becomes active. Otherwise, the cube
+					// remains inactive.
+					if state == 0 {
+						if nactive == 3 {
+							changes[Point{x, y, z, h}] = 1
+						}
+					}
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// Count the number of active cubes
+func countActive() int {
+	tot := 0
+	for _, n := range current {
+		tot += n
+	}
+	return tot
+}
+
+// Draw the current state of one z/w slice, covering the x/y extent of the
+// whole space, with # for active cubes; also returns the x/y of the top left
+func renderSlice(z, h int) ([][]byte, int, int) {
+	min, max := getDims()
+	rows := [][]byte{}
+	for y := min.y; y <= max.y; y++ {
+		row := []byte{}
+		for x := min.x; x <= max.x; x++ {
+			if getCurrentState(x, y, z, h) == 1 {
+				row = append(row, '#')
+			} else {
+				row = append(row, '.')
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, min.x, min.y
+}
+
+// Get current 1/0 state of cube at specific x/y/z/h
+func getCurrentState(x, y, z, h int) int {
+	c, ok := current[Point{x, y, z, h}]
+	if ok {
+		return c
+	} else {
+		return 0 // assume inactive if not defined
+	}
+}
+
+// Set current 1/0 state of cube at specific x/y/z/h
+func setCurrentState(x, y, z, h, state int) {
+	current[Point{x, y, z, h}] = state
+}
+
+// Set next 1/0 state of cube at specific x/y/z/h
+func setNextState(x, y, z, h, state int) {
+	next[Point{x, y, z, h}] = state
+}
+
+// Roll next states over to current
+func rollOver() {
+
+	// Copy the changed values to the current map. Do NOT clear out
+	// the current map first, as this would only copy things that changed
+	// in the last iteration
+	for p, st := range next {
+		current[p] = st
+	}
+
+	// Clear out the map of change for the next iteration
+	next = map[Point]int{}
+}
+
+// Get the current number of active neighbours for an x/y/z/h coordinate.
+// Basically just look -1/0/1 in each dimension, but don't include the
+// central cube itself.
+func activeNeighbours(x, y, z, h int) int {
+	diffs := []int{-1, 0, 1}
+	var nactive int
+	for _, dx := range diffs {
+		for _, dy := range diffs {
+			for _, dz := range diffs {
+				for _, dh := range diffs {
+					if !(dx == 0 && dy == 0 && dz == 0 && dh == 0) {
+						nactive += getCurrentState(x+dx, y+dy, z+dz, h+dh)
+					}
+				}
+			}
+		}
+	}
+	return nactive
+}
+
+// Get dimensions of the data set, i.e., the min/max x/y/z of all currently defined points
+func getDims() (Point, Point) {
+
+	var min, max Point
+	for p, _ := range current {
+
+		// Min/max x values
+		if p.x < min.x {
+			min.x = p.x
+		}
+		if p.x > max.x {
+			max.x = p.x
+		}
+
+		// Min/max y values
+		if p.y < min.y {
+			min.y = p.y
+		}
+		if p.y > max.y {
+			max.y = p.y
+		}
+
+		// Min/max z values
+		if p.z < min.z {
+			min.z = p.z
+		}
+		if p.z > max.z {
+			max.z = p.z
+		}
+
+		// Min/max h values
+		if p.h < min.h {
+			min.h = p.h
+		}
+		if p.h > max.h {
+			max.h = p.h
+		}
+	}
+
+	return min, max
+}
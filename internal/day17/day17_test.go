@@ -0,0 +1,68 @@
+// Unit tests and benchmarks for Day 17
+
+package day17
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+// Run a number of iterations from a file, returning the final state (see
+// state.go) and the number of active cubes
+func runIterations(t *testing.T, fname string, iters, workers int) (string, int) {
+	data, err := ioutil.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readState(data)
+	for i := 0; i < iters; i++ {
+		step(workers)
+		rollOver()
+	}
+	return string(activeState()), countActive()
+}
+
+// Test Part 2 on the example from the problem description
+func TestSample(t *testing.T) {
+	if _, n := runIterations(t, "sample.txt", 6, 1); n != 848 {
+		t.Error("Sample should have 848 active cubes, not", n)
+	}
+}
+
+// Sharing the work between goroutines should give exactly the same state
+// as one goroutine. Run with go test -race to check the workers don't share
+// anything they shouldn't.
+func TestWorkersMatchSequential(t *testing.T) {
+	h1, n1 := runIterations(t, "input.txt", 4, 1)
+	for _, workers := range []int{2, 3, 7, 1000} {
+		h, n := runIterations(t, "input.txt", 4, workers)
+		if h != h1 || n != n1 {
+			t.Error("Workers", workers, "gave", n, "active cubes, not", n1)
+		}
+	}
+}
+
+// Part 2 on the puzzle input (there is no BenchmarkPart1, since only Part 2
+// is in Go)
+func BenchmarkPart2(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	for i := 0; i < b.N; i++ {
+		readState(data)
+		for j := 0; j < 6; j++ {
+			step(1)
+			rollOver()
+		}
+	}
+}
+
+// Part 2 on the puzzle input, with four workers
+func BenchmarkPart2Workers(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	for i := 0; i < b.N; i++ {
+		readState(data)
+		for j := 0; j < 6; j++ {
+			step(4)
+			rollOver()
+		}
+	}
+}
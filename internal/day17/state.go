@@ -0,0 +1,40 @@
+// Encoding of the set of active cubes after each iteration of day 17, so a
+// state that repeats an earlier one can be recognised (see internal/cycle)
+
+package day17
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// The current set of active cubes, as their coordinates in sorted order so
+// that the state does not depend on the order of the map
+func activeState() []byte {
+	active := []Point{}
+	for p, st := range current {
+		if st == 1 {
+			active = append(active, p)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		a, b := active[i], active[j]
+		if a.x != b.x {
+			return a.x < b.x
+		}
+		if a.y != b.y {
+			return a.y < b.y
+		}
+		if a.z != b.z {
+			return a.z < b.z
+		}
+		return a.h < b.h
+	})
+	buf := []byte{}
+	for _, p := range active {
+		for _, n := range []int{p.x, p.y, p.z, p.h} {
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(n))
+		}
+	}
+	return buf
+}
@@ -10,7 +10,7 @@
 //
 // AK, 17/10/2022
 
-package main
+package day18
 
 import (
 	"fmt"
@@ -19,33 +19,45 @@ import (
 	"strings"
 )
 
-func main() {
+func Main(args []string) {
 
-	// Evaluate each equation and add up answers
-	// Sample.txt: 71, 51, 26, 437, 12240, 13632
-	tot := 0
+	// Evaluate each equation and add up answers, for both parts
+	// Sample.txt: 71, 51, 26, 437, 12240, 13632 (Part 2)
 	data, _ := ioutil.ReadFile("input.txt")
-	for _, expr := range strings.Split(string(data), "\n") {
-		fmt.Println("Expr:", expr)
-		tree := parse(expr)
-		val := evaluate(tree)
-		fmt.Println(" =", val)
-		tot += val
+	exprs := strings.Split(string(data), "\n")
+	for _, expr := range exprs {
+		if len(expr) > 0 {
+			fmt.Println("Expr:", expr)
+			fmt.Println(" =", evaluate(parse(expr, false)), "(Part 1),",
+				evaluate(parse(expr, true)), "(Part 2)")
+		}
 	}
+	fmt.Println("Part 1 total =", total(exprs, false))
+	fmt.Println("Part 2 total =", total(exprs, true))
+}
 
-	fmt.Println("Total =", tot)
+// Add up the values of all expressions, skipping blank lines
+func total(exprs []string, part2 bool) int {
+	tot := 0
+	for _, expr := range exprs {
+		if len(expr) > 0 {
+			tot += evaluate(parse(expr, part2))
+		}
+	}
+	return tot
 }
 
 // Parse an expression, return list of tokens in postfix notation
-func parse(expr string) []string {
+func parse(expr string, part2 bool) []string {
 
 	// Precendence of different operaters, same for part 1
 	precedence := map[string]int{"+": 1, "-": 1, "*": 1, "/": 1}
 
-	// Uncomment these line for Part 2, where addition and subtraction have
-	// higher precedence
-	precedence["+"] = 2
-	precedence["-"] = 2
+	// For Part 2, addition and subtraction have higher precedence
+	if part2 {
+		precedence["+"] = 2
+		precedence["-"] = 2
+	}
 
 	// Output and operator stacks are just lists
 	output := []string{}
@@ -0,0 +1,42 @@
+// Unit tests and benchmarks for Day 18
+
+package day18
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// Examples from the problem description, for both parts
+func TestSample(t *testing.T) {
+	data, _ := ioutil.ReadFile("sample.txt")
+	want1 := []int{71, 51, 26, 437, 12240, 13632}
+	want2 := []int{231, 51, 46, 1445, 669060, 23340}
+	for i, expr := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if v := evaluate(parse(expr, false)); v != want1[i] {
+			t.Errorf("Part 1: %s should be %d, not %d", expr, want1[i], v)
+		}
+		if v := evaluate(parse(expr, true)); v != want2[i] {
+			t.Errorf("Part 2: %s should be %d, not %d", expr, want2[i], v)
+		}
+	}
+}
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	exprs := strings.Split(string(data), "\n")
+	for i := 0; i < b.N; i++ {
+		total(exprs, false)
+	}
+}
+
+// Part 2 on the puzzle input
+func BenchmarkPart2(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	exprs := strings.Split(string(data), "\n")
+	for i := 0; i < b.N; i++ {
+		total(exprs, true)
+	}
+}
@@ -2,7 +2,7 @@
 //
 // AK, 18/10/2022
 
-package main
+package day19
 
 import (
 	"fmt"
@@ -20,52 +20,82 @@ type Rule struct {
 	R    []int // sub-rule numbers, right part
 }
 
-func main() {
+func Main(args []string) {
 
 	// Read rules and messages from input file
-	filename := "sample.txt" // 2 matches (part 1)
-	//filename := "sample2.txt" // 2/12 matches (part 1/2)
-	// filename := "input.txt" // 156/363
-	//readData("sample.txt")
+	//filename := "sample.txt" // 2 matches (part 1)
+	//filename := "sample2.txt" // 3/12 matches (part 1/2)
+	filename := "input.txt" // 156/363
 	rules, messages := readData(filename)
-	fmt.Println(rules)
-	fmt.Println(messages)
 
-	// Test each message against rule zero
+	// Part 1: count messages that match rule zero
+	fmt.Println("Part 1 (s/b 156):", countMatches(messages, rules))
+
+	// Part 2: replace rules 8 and 11 with rules that loop, and count again
+	loopRules(rules)
+	fmt.Println("Part 2 (s/b 363):", countMatches(messages, rules))
+}
+
+// Replace rules 8 and 11 with the rules for Part 2, which refer to
+// themselves:
+// 8: 42 | 42 8
+// 11: 42 31 | 42 11 31
+func loopRules(rules map[int]Rule) {
+	rules[8] = Rule{num: 8, L: []int{42}, R: []int{42, 8}}
+	rules[11] = Rule{num: 11, L: []int{42, 31}, R: []int{42, 11, 31}}
+}
+
+// Count the messages that match rule zero
+func countMatches(messages []string, rules map[int]Rule) int {
+	n := 0
 	for _, msg := range messages {
-		fmt.Println(msg, "=>", match(msg, rules[0], rules))
+		if match(msg, []int{0}, rules) {
+			n++
+		}
 	}
+	return n
 }
 
-func match(msg string, rule Rule, rules []Rule) bool {
+// Check whether a message matches a sequence of rules, by matching the first
+// rule against the start of the message, and the rest of the sequence
+// against the rest of the message. Rules that loop (Part 2) still finish,
+// because the first rule in the sequence is always expanded first.
+func match(msg string, seq []int, rules map[int]Rule) bool {
 
 	// If both empty, return true (pattern has been matched)
-	if len(msg) == 0 && len(rules) == 0 {
+	if len(msg) == 0 && len(seq) == 0 {
 		return true
 	}
 
-	// If one is empty but not the other, return false (cannot match)
-	if len(msg) == 0 || len(rules) == 0 {
+	// If one is empty but not the other, return false (cannot match); also
+	// fail if there are more rules than characters left, since every rule
+	// matches at least one character
+	if len(msg) == 0 || len(seq) == 0 || len(seq) > len(msg) {
 		return false
 	}
 
-	// Take the first part of this rule
-	r := rules[rule[0]]
+	// Take the first rule in the sequence
+	r := rules[seq[0]]
 
 	// If it's a letter and matches start of string, check rest, otherwise fail
 	if r.char != 0 {
 		if msg[0] == r.char {
-			return match(s[1:], rule[1:], rules)
-		} else {
-			return false
+			return match(msg[1:], seq[1:], rules)
 		}
+		return false
 	}
 
-	// Check remaining patterns: for each term in the current rule, check
-	// it plus the remaining rules
-	//for t in r:
-	//    if test(s, t + seq[1:]):
-	//        return True
+	// Check each alternative of the current rule, followed by the remaining
+	// rules
+	for _, alt := range [][]int{r.L, r.R} {
+		if len(alt) == 0 {
+			continue
+		}
+		next := append(append([]int{}, alt...), seq[1:]...)
+		if match(msg, next, rules) {
+			return true
+		}
+	}
 
 	// All failed
 	return false
@@ -0,0 +1,44 @@
+// Unit tests and benchmarks for Day 19
+
+package day19
+
+import (
+	"testing"
+)
+
+// Test the first example from the problem description
+func TestSample(t *testing.T) {
+	rules, messages := readData("sample.txt")
+	if n := countMatches(messages, rules); n != 2 {
+		t.Error("Sample should have 2 matches, not", n)
+	}
+}
+
+// Test the second example, before and after changing rules 8 and 11
+func TestSample2(t *testing.T) {
+	rules, messages := readData("sample2.txt")
+	if n := countMatches(messages, rules); n != 3 {
+		t.Error("Sample 2 should have 3 matches, not", n)
+	}
+	loopRules(rules)
+	if n := countMatches(messages, rules); n != 12 {
+		t.Error("Sample 2 should have 12 matches with looping rules, not", n)
+	}
+}
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	rules, messages := readData("input.txt")
+	for i := 0; i < b.N; i++ {
+		countMatches(messages, rules)
+	}
+}
+
+// Part 2 on the puzzle input
+func BenchmarkPart2(b *testing.B) {
+	rules, messages := readData("input.txt")
+	loopRules(rules)
+	for i := 0; i < b.N; i++ {
+		countMatches(messages, rules)
+	}
+}
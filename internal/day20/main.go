@@ -9,7 +9,7 @@
 //
 // AK, 23/11/2022
 
-package main
+package day20
 
 import (
 	"fmt"
@@ -35,7 +35,7 @@ type Position struct {
 	row, col int
 }
 
-func main() {
+func Main(args []string) {
 
 	//// Read tiles, extract the edges
 	//tiles := readTiles("sample.txt")
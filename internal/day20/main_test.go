@@ -0,0 +1,32 @@
+// Benchmarks for Day 20
+
+package day20
+
+import (
+	"testing"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/testutil"
+)
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	tiles := readTiles("input.txt")
+	testutil.Quiet(func() {
+		for i := 0; i < b.N; i++ {
+			part1(tiles)
+		}
+	})
+}
+
+// Part 2 on the puzzle input, which places the tiles, so they are read
+// again (not timed) for each run
+func BenchmarkPart2(b *testing.B) {
+	testutil.Quiet(func() {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			tiles := readTiles("input.txt")
+			b.StartTimer()
+			part2(tiles)
+		}
+	})
+}
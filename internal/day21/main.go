@@ -25,7 +25,7 @@
 //
 // AK, 24/11/2022
 
-package main
+package day21
 
 import (
 	"fmt"
@@ -38,12 +38,56 @@ type Rule struct {
 	ingreds, allerg []string
 }
 
-func main() {
+func Main(args []string) {
 
 	// Read input into a list of rules
 	//rules := readInput("sample.txt")
 	rules := readInput("input.txt")
 
+	// Part 1: ingredients that can't contain any allergen, and the number of
+	// times they appear
+	ans, occ, union := part1(rules)
+	fmt.Println("Part 1 (should be kfcds, nhms, sbzzf, trh):", ans)
+	fmt.Printf("Part 1: ingredients appear %d times\n", occ)
+
+	// Part 2 is the list of ingredients, sorted by allergen
+	// For sample, should be: mxmxvkd,sqjhc,fvjkl.
+	// because mxmxvkd contains dairy.
+	//         sqjhc contains fish.
+	//         fvjkl contains soy.
+	fmt.Println("Part 2 (need to manually reduce and sort):", union)
+
+	// Outputs from part 2, reduced and sorted manually:
+	//
+	// fish => [cskbmx jrmr]
+	// shellfish => [tzxcmr jrmr]
+	// wheat => [jrmr cjdmk cskbmx fxzh]
+	// nuts => [cjdmk cskbmx xlxknk]
+	// dairy => [xlxknk jrmr cskbmx]
+	// sesame => [jrmr]
+	// peanuts => [cskbmx xlxknk bmhn]
+	// soy => [fmgxh bmhn]
+	//
+	// Reduced and sorted:
+	// dairy => [xlxknk]
+	// fish => [cskbmx ]
+	// nuts => [cjdmk]
+	// peanuts => [bmhn]
+	// sesame => [jrmr]
+	// shellfish => [tzxcmr]
+	// soy => [fmgxh]
+	// wheat => [fxzh]
+	//
+	// Answer:  xlxknk,cskbmx,cjdmk,bmhn,jrmr,tzxcmr,fmgxh,fxzh
+
+}
+
+// Part 1: find the ingredients that can't contain any allergen, i.e., those
+// not common to all the recipes for any allergen, and the number of times
+// they appear in recipes. Also returns the ingredients that may contain
+// allergens, for Part 2.
+func part1(rules []Rule) ([]string, int, []string) {
+
 	// Get the sets of all allergens and all ingredients
 	allergens := []string{}
 	ingreds := []string{}
@@ -83,45 +127,13 @@ func main() {
 	// Part 1 answer is the difference between all ingredients and the union
 	// For sample.txt, should be kfcds, nhms, sbzzf, or trh
 	ans := difference(ingreds, union)
-	fmt.Println("Part 1 (should be kfcds, nhms, sbzzf, trh):", ans)
 
 	// Count up the number of times these ingredients appear
 	occ := 0 // number of times these ingredients occur
 	for _, i := range ans {
 		occ += occurences(i, rules)
 	}
-	fmt.Printf("Part 1: ingredients appear %d times\n", occ)
-
-	// Part 2 is the list of ingredients, sorted by allergen
-	// For sample, should be: mxmxvkd,sqjhc,fvjkl.
-	// because mxmxvkd contains dairy.
-	//         sqjhc contains fish.
-	//         fvjkl contains soy.
-	fmt.Println("Part 2 (need to manually reduce and sort):", union)
-
-	// Outputs from part 2, reduced and sorted manually:
-	//
-	// fish => [cskbmx jrmr]
-	// shellfish => [tzxcmr jrmr]
-	// wheat => [jrmr cjdmk cskbmx fxzh]
-	// nuts => [cjdmk cskbmx xlxknk]
-	// dairy => [xlxknk jrmr cskbmx]
-	// sesame => [jrmr]
-	// peanuts => [cskbmx xlxknk bmhn]
-	// soy => [fmgxh bmhn]
-	//
-	// Reduced and sorted:
-	// dairy => [xlxknk]
-	// fish => [cskbmx ]
-	// nuts => [cjdmk]
-	// peanuts => [bmhn]
-	// sesame => [jrmr]
-	// shellfish => [tzxcmr]
-	// soy => [fmgxh]
-	// wheat => [fxzh]
-	//
-	// Answer:  xlxknk,cskbmx,cjdmk,bmhn,jrmr,tzxcmr,fmgxh,fxzh
-
+	return ans, occ, union
 }
 
 // Count the number of occurrences of ingredient in list of rules
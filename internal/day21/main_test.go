@@ -0,0 +1,30 @@
+// Unit tests and benchmarks for Day 21
+
+package day21
+
+import (
+	"testing"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/testutil"
+)
+
+// Part 1 example from the problem description
+func TestPart1Sample(t *testing.T) {
+	var occ int
+	testutil.Quiet(func() {
+		_, occ, _ = part1(readInput("sample.txt"))
+	})
+	if occ != 5 {
+		t.Error("Ingredients should appear 5 times, not", occ)
+	}
+}
+
+// Part 1 on the puzzle input (Part 2 was finished by hand, see main)
+func BenchmarkPart1(b *testing.B) {
+	rules := readInput("input.txt")
+	testutil.Quiet(func() {
+		for i := 0; i < b.N; i++ {
+			part1(rules)
+		}
+	})
+}
@@ -0,0 +1,241 @@
+// Advent of Code 2020, Day 22
+//
+// Two players play a card game ("Combat"), each drawing the top card of
+// their deck every round, and the higher card wins both. For Part 2, play
+// "Recursive Combat", where a round may be decided by a sub-game played with
+// copies of the top few cards, and a game that repeats an earlier position
+// is won by player 1. Report the score of the winner's deck.
+//
+// Every game, round and sub-game can be written to a log as JSON lines (see
+// log.go), which can be replayed and checked against the rules.
+//
+// AK, x/x/2022
+
+package day22
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Plays a game of Combat or Recursive Combat, including any sub-games
+type Game struct {
+	Recursive bool           // play Recursive Combat (Part 2)
+	Log       func(LogEntry) // called with every event if not nil
+	games     int            // number of games started, including sub-games
+	seen      []*Positions   // positions of the game at each depth, reused by later sub-games
+}
+
+func Main(args []string) {
+
+	// Command line options
+	fs := flag.NewFlagSet("day22", flag.ExitOnError)
+	fname := fs.String("input", "input.txt", "input file")
+	verbose := fs.Bool("v", false, "print every round")
+	logFile := fs.String("log", "", "write a log of every round of both parts to this file, as JSON lines")
+	replay := fs.String("replay", "", "replay a log file and check it against the rules, instead of playing")
+	explore := fs.Int("explore", 0, "play this many games of Recursive Combat with random decks, and report statistics")
+	generate := fs.String("generate", "", "write random decks to this file, in the same format as the input")
+	cards := fs.Int("cards", 50, "number of cards in random decks")
+	seed := fs.Int64("seed", 1, "random seed for random decks")
+	fs.Parse(args)
+
+	// Generate random decks, or explore statistics of random games, if
+	// requested
+	if *generate != "" || *explore > 0 {
+		if *generate != "" {
+			player1, player2, err := dealDecks(rand.New(rand.NewSource(*seed)), *cards)
+			if err == nil {
+				err = writeDecks(*generate, player1, player2)
+			}
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+		}
+		if *explore > 0 {
+			if err := exploreGames(*explore, *cards, *seed); err != nil {
+				fmt.Println("Error:", err)
+			}
+		}
+		return
+	}
+
+	// Replay a log instead of playing, if requested
+	if *replay != "" {
+		games, rounds, err := replayLog(*replay)
+		if err != nil {
+			fmt.Println("Invalid log:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Log is valid: %d games, %d rounds\n", games, rounds)
+		return
+	}
+
+	// Read both decks of cards
+	data, err := ioutil.ReadFile(*fname)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	player1, player2, err := readDecks(string(data))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Open log file if required
+	var logger func(LogEntry)
+	if *logFile != "" {
+		f, err := os.Create(*logFile)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		defer f.Close()
+		logger = jsonLogger(f)
+	}
+	if *verbose {
+		logger = combineLoggers(logger, printEntry)
+	}
+
+	// Part 1 is plain Combat, Part 2 is Recursive Combat
+	for part, recursive := range []bool{false, true} {
+		g := Game{Recursive: recursive, Log: logger}
+		winner, deck := g.Play(player1, player2)
+		fmt.Printf("Part %d: player %d wins after %d games, score = %d\n",
+			part+1, winner, g.games, score(deck))
+	}
+}
+
+// Read the decks of both players, each card being a positive number
+func readDecks(data string) ([]int, []int, error) {
+	var player1, player2 []int
+	reading2 := false
+	for i, l := range strings.Split(data, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "Player 1:" || len(l) == 0 {
+			continue
+		} else if l == "Player 2:" {
+			reading2 = true
+			continue
+		}
+		c, err := strconv.Atoi(l)
+		if err != nil || c < 1 {
+			return nil, nil, fmt.Errorf("line %d: invalid card %q", i+1, l)
+		}
+		if reading2 {
+			player2 = append(player2, c)
+		} else {
+			player1 = append(player1, c)
+		}
+	}
+	return player1, player2, nil
+}
+
+// Play a game from the given decks (which are not changed), returning the
+// winner (1 or 2) and their final deck
+func (g *Game) Play(deck1, deck2 []int) (int, []int) {
+	g.games = 0
+	n := len(deck1) + len(deck2)
+	winner, deck := g.play(newDeck(deck1, n), newDeck(deck2, n), 0)
+	return winner, deck.Cards()
+}
+
+// Play a game or sub-game at the given depth (0 for the main game), until
+// one player has no cards left, or (for Recursive Combat) the decks repeat
+// an earlier position in the same game. Each deck must have room for all
+// the cards in the game.
+func (g *Game) play(deck1, deck2 *Deck, depth int) (int, *Deck) {
+
+	g.games++
+	game := g.games
+	g.log(LogEntry{Event: "start", Game: game, Depth: depth, Recursive: g.Recursive},
+		deck1, deck2)
+
+	seen := g.positions(depth)
+	for round := 1; deck1.Len() > 0 && deck2.Len() > 0; round++ {
+
+		// Recursive Combat: player 1 wins if this position has already
+		// been seen in this game
+		if g.Recursive && seen.Seen(deck1, deck2) {
+			g.log(LogEntry{Event: "end", Game: game, Depth: depth, Winner: 1, Repeat: true},
+				deck1, deck2)
+			return 1, deck1
+		}
+
+		// Draw cards
+		card1, card2 := deck1.PopFront(), deck2.PopFront()
+
+		// Recursive Combat: if both players have enough cards, the winner
+		// of the round is the winner of a sub-game, played with copies of
+		// as many cards as the card they drew. Otherwise, the higher card
+		// wins.
+		winner, sub := 2, 0
+		if g.Recursive && card1 > 0 && card2 > 0 && deck1.Len() >= card1 && deck2.Len() >= card2 {
+			sub = g.games + 1
+			n := card1 + card2
+			winner, _ = g.play(deck1.CopyPrefix(card1, n), deck2.CopyPrefix(card2, n), depth+1)
+		} else if card1 > card2 {
+			winner = 1
+		}
+
+		// Winner keeps both cards, their own first
+		if winner == 1 {
+			deck1.PushBack(card1)
+			deck1.PushBack(card2)
+		} else {
+			deck2.PushBack(card2)
+			deck2.PushBack(card1)
+		}
+		g.log(LogEntry{Event: "round", Game: game, Depth: depth, Round: round,
+			Card1: card1, Card2: card2, Winner: winner, SubGame: sub}, deck1, deck2)
+	}
+
+	// Winner is the player with cards left
+	if deck1.Len() > 0 {
+		g.log(LogEntry{Event: "end", Game: game, Depth: depth, Winner: 1}, deck1, deck2)
+		return 1, deck1
+	}
+	g.log(LogEntry{Event: "end", Game: game, Depth: depth, Winner: 2}, deck1, deck2)
+	return 2, deck2
+}
+
+// Empty record of positions for a game at the given depth. Only one game at
+// each depth is in progress at a time, so the same one is reused by every
+// sub-game at that depth, saving allocation.
+func (g *Game) positions(depth int) *Positions {
+	for len(g.seen) <= depth {
+		g.seen = append(g.seen, newPositions())
+	}
+	g.seen[depth].Reset()
+	return g.seen[depth]
+}
+
+// Send an entry to the log with the cards in each deck, if there is a log
+func (g *Game) log(e LogEntry, deck1, deck2 *Deck) {
+	if g.Log != nil {
+		e.Deck1 = deck1.Cards()
+		e.Deck2 = deck2.Cards()
+		g.Log(e)
+	}
+}
+
+// Copy a deck, so changes don't affect the original
+func copyDeck(deck []int) []int {
+	return append([]int{}, deck...)
+}
+
+// Score of a deck: bottom card times 1, next card times 2, etc.
+func score(deck []int) int {
+	result := 0
+	for i := 0; i < len(deck); i++ {
+		result += (i + 1) * deck[len(deck)-i-1]
+	}
+	return result
+}
@@ -0,0 +1,226 @@
+// Unit tests and benchmarks for Day 22
+
+package day22
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Play a game from a file, returning the winning score and the log
+func playFile(t *testing.T, filename string, recursive bool) (int, []byte) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player1, player2, err := readDecks(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	g := Game{Recursive: recursive, Log: jsonLogger(&buf)}
+	_, deck := g.Play(player1, player2)
+	return score(deck), buf.Bytes()
+}
+
+// Test the example from the problem description
+func TestSample(t *testing.T) {
+	if s, _ := playFile(t, "sample.txt", false); s != 306 {
+		t.Error("Part 1 score should be 306, not", s)
+	}
+	if s, _ := playFile(t, "sample.txt", true); s != 291 {
+		t.Error("Part 2 score should be 291, not", s)
+	}
+}
+
+// Logs of both parts should replay without errors
+func TestReplay(t *testing.T) {
+	for _, fname := range []string{"sample.txt", "input.txt"} {
+		for _, recursive := range []bool{false, true} {
+			_, log := playFile(t, fname, recursive)
+			games, _, err := validateLog(bytes.NewReader(log))
+			if err != nil {
+				t.Error(fname, recursive, err)
+			}
+			if !recursive && games != 1 {
+				t.Error("Part 1 should be a single game, not", games)
+			}
+		}
+	}
+}
+
+// Changing any round of a log should make it invalid
+func TestReplayTampered(t *testing.T) {
+	_, log := playFile(t, "sample.txt", true)
+	lines := strings.Split(strings.TrimSpace(string(log)), "\n")
+	for i, l := range lines {
+		for _, change := range [][2]string{
+			{`"winner":1`, `"winner":2`},
+			{`"winner":2`, `"winner":1`},
+			{`"deck1":[`, `"deck1":[99,`},
+			{`"repeat":true,`, ``},
+			{`"deck1":[`, `"deck1":[-1,`},
+			{`"deck2":[`, `"deck2":[0,`},
+		} {
+			if !strings.Contains(l, change[0]) {
+				continue
+			}
+			bad := append(append([]string{}, lines[:i]...), strings.Replace(l, change[0], change[1], 1))
+			bad = append(bad, lines[i+1:]...)
+			if _, _, err := validateLog(strings.NewReader(strings.Join(bad, "\n"))); err == nil {
+				t.Errorf("Line %d changed from %s to %s, but log is still valid", i+1, change[0], change[1])
+			}
+		}
+	}
+}
+
+// A negative card that would start a sub-game should be an error, not a
+// panic from using it as the size of the sub-game's deck
+func TestReplayNegativeCard(t *testing.T) {
+	log := `{"event":"start","game":1,"depth":0,"recursive":true,"deck1":[-1,5],"deck2":[1,2]}
+{"event":"start","game":2,"depth":1,"recursive":true,"deck1":[],"deck2":[2]}`
+	if _, _, err := validateLog(strings.NewReader(log)); err == nil {
+		t.Error("Log with a negative card should be invalid")
+	}
+}
+
+// Cards that are not positive numbers should be errors
+func TestReadDecksInvalid(t *testing.T) {
+	for _, data := range []string{
+		"Player 1:\n9\n-2\n\nPlayer 2:\n5\n",
+		"Player 1:\n9\n2\n\nPlayer 2:\n0\n",
+		"Player 1:\n9\nx\n\nPlayer 2:\n5\n",
+	} {
+		if _, _, err := readDecks(data); err == nil {
+			t.Errorf("Should be an error: %q", data)
+		}
+	}
+}
+
+// Deck should wrap around its buffer, and decks with the same cards should
+// have the same hash wherever they start in the buffer
+func TestDeck(t *testing.T) {
+	d := newDeck([]int{1, 2, 3}, 4)
+	for i := 4; i < 20; i++ {
+		if c := d.PopFront(); c != i-3 {
+			t.Error("Drew", c, "instead of", i-3)
+		}
+		d.PushBack(i)
+	}
+	if !sameDeck(d.Cards(), []int{17, 18, 19}) {
+		t.Error("Deck should be 17,18,19, not", d.Cards())
+	}
+	d2 := newDeck([]int{17, 18, 19}, 3)
+	if d.Hash(fnvOffset) != d2.Hash(fnvOffset) {
+		t.Error("Same cards should have the same hash")
+	}
+	p := d.CopyPrefix(2, 5)
+	if !sameDeck(p.Cards(), []int{17, 18}) || len(p.cards) != 5 {
+		t.Error("Prefix should be 17,18 with capacity 5, not", p.Cards(), len(p.cards))
+	}
+	if hashDecks(p, d2) == hashDecks(d2, p) {
+		t.Error("Swapping decks should change the hash")
+	}
+}
+
+// A position should only count as a repeat if the cards are the same, not
+// just the hash, so plant a different position under the same hash
+func TestPositions(t *testing.T) {
+	d1, d2 := newDeck([]int{9, 2}, 5), newDeck([]int{5, 8, 1}, 5)
+	seen := newPositions()
+	seen.index[hashDecks(d1, d2)] = []int{0}
+	seen.cards = []int{2, 2, 9, 5, 8, 1}
+	if seen.Seen(d1, d2) {
+		t.Error("Different position with the same hash counted as a repeat")
+	}
+	d1.PushBack(d1.PopFront()) // same cards, different buffer offset
+	d1.PushBack(d1.PopFront())
+	if !seen.Seen(d1, d2) {
+		t.Error("Same position not counted as a repeat")
+	}
+}
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	player1, player2, _ := readDecks(string(data))
+	for i := 0; i < b.N; i++ {
+		g := Game{}
+		g.Play(player1, player2)
+	}
+}
+
+// Part 2 on the puzzle input
+func BenchmarkPart2(b *testing.B) {
+	data, _ := ioutil.ReadFile("input.txt")
+	player1, player2, _ := readDecks(string(data))
+	for i := 0; i < b.N; i++ {
+		g := Game{Recursive: true}
+		g.Play(player1, player2)
+	}
+}
+
+// Random decks should contain each card once, and be the same for the
+// same seed
+func TestDealDecks(t *testing.T) {
+	p1, p2, _ := dealDecks(rand.New(rand.NewSource(5)), 11)
+	q1, q2, _ := dealDecks(rand.New(rand.NewSource(5)), 11)
+	if len(p1) != 5 || len(p2) != 6 || !sameDeck(p1, q1) || !sameDeck(p2, q2) {
+		t.Error("Decks not dealt consistently:", p1, p2, q1, q2)
+	}
+	seen := map[int]bool{}
+	for _, c := range append(p1, p2...) {
+		seen[c] = true
+	}
+	for c := 1; c <= 11; c++ {
+		if !seen[c] {
+			t.Error("Card missing from decks:", c)
+		}
+	}
+	if p1, p2, err := dealDecks(rand.New(rand.NewSource(5)), 2); err != nil || len(p1) != 1 || len(p2) != 1 {
+		t.Error("2 cards should give one each, not", p1, p2, err)
+	}
+	for _, n := range []int{1, 0, -1} {
+		if _, _, err := dealDecks(rand.New(rand.NewSource(5)), n); err == nil {
+			t.Error(n, "cards should be an error")
+		}
+	}
+}
+
+// Decks written should read back the same, and errors writing them should
+// be returned
+func TestWriteDecks(t *testing.T) {
+	fname := t.TempDir() + "/decks.txt"
+	if err := writeDecks(fname, []int{3, 1}, []int{2, 4, 5}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadFile(fname)
+	p1, p2, err := readDecks(string(data))
+	if err != nil || !sameDeck(p1, []int{3, 1}) || !sameDeck(p2, []int{2, 4, 5}) {
+		t.Error("Decks read back as", p1, p2, err)
+	}
+	if err := writeDecks(t.TempDir()+"/missing/decks.txt", p1, p2); err == nil {
+		t.Error("Creating a file in a missing directory should be an error")
+	}
+	if _, err := os.Stat("/dev/full"); err == nil {
+		if err := writeDecks("/dev/full", p1, p2); err == nil {
+			t.Error("Writing to a full device should be an error")
+		}
+	}
+}
+
+// Statistics should agree with a replay of the log
+func TestPlayStats(t *testing.T) {
+	data, _ := ioutil.ReadFile("sample.txt")
+	player1, player2, _ := readDecks(string(data))
+	st := playStats(player1, player2)
+	_, log := playFile(t, "sample.txt", true)
+	games, rounds, _ := validateLog(bytes.NewReader(log))
+	if st.Winner != 2 || st.TotalRounds != rounds || st.SubGames != games-1 {
+		t.Error("Statistics do not match log:", st, games, rounds)
+	}
+}
@@ -0,0 +1,142 @@
+// A deck of cards for day 22, as a ring buffer with a fixed capacity, so
+// drawing from the top and adding to the bottom never reallocates
+
+package day22
+
+// FNV-1a constants, for hashing decks
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// Cards in a deck, the top card at cards[front], wrapping around to the
+// start of the buffer
+type Deck struct {
+	cards []int // buffer, length is the capacity of the deck
+	front int   // index of top card
+	n     int   // number of cards in the deck
+}
+
+// Create a deck with the given cards, top card first, with room for the
+// given number of cards (at least the number given)
+func newDeck(cards []int, capacity int) *Deck {
+	d := &Deck{cards: make([]int, max(capacity, len(cards), 1))}
+	for _, c := range cards {
+		d.PushBack(c)
+	}
+	return d
+}
+
+// Number of cards in the deck
+func (d *Deck) Len() int {
+	return d.n
+}
+
+// Remove and return the top card
+func (d *Deck) PopFront() int {
+	if d.n == 0 {
+		panic("draw from empty deck")
+	}
+	c := d.cards[d.front]
+	d.front = (d.front + 1) % len(d.cards)
+	d.n--
+	return c
+}
+
+// Add a card to the bottom of the deck
+func (d *Deck) PushBack(c int) {
+	if d.n == len(d.cards) {
+		panic("deck is full")
+	}
+	d.cards[(d.front+d.n)%len(d.cards)] = c
+	d.n++
+}
+
+// New deck with copies of the top n cards, with room for the given number
+// of cards
+func (d *Deck) CopyPrefix(n, capacity int) *Deck {
+	nd := &Deck{cards: make([]int, max(capacity, n, 1))}
+	for i := 0; i < n; i++ {
+		nd.cards[i] = d.cards[(d.front+i)%len(d.cards)]
+	}
+	nd.n = n
+	return nd
+}
+
+// Cards in the deck as a new slice, top card first
+func (d *Deck) Cards() []int {
+	return d.appendTo(make([]int, 0, d.n))
+}
+
+// Append the cards in the deck to a slice, top card first: the cards up to
+// the end of the buffer, then any that have wrapped round to the start
+func (d *Deck) appendTo(cards []int) []int {
+	end := min(d.front+d.n, len(d.cards))
+	cards = append(cards, d.cards[d.front:end]...)
+	return append(cards, d.cards[:d.n-(end-d.front)]...)
+}
+
+// Continue an FNV-1a hash with the number of cards in the deck, then each
+// card in order, without allocating
+func (d *Deck) Hash(h uint64) uint64 {
+	h = (h ^ uint64(d.n)) * fnvPrime
+	for i := 0; i < d.n; i++ {
+		h = (h ^ uint64(d.cards[(d.front+i)%len(d.cards)])) * fnvPrime
+	}
+	return h
+}
+
+// Hash of the position of a game, i.e., both decks
+func hashDecks(d1, d2 *Deck) uint64 {
+	return d2.Hash(d1.Hash(fnvOffset))
+}
+
+// Whether the deck holds exactly the given cards, top card first
+func (d *Deck) Equal(cards []int) bool {
+	if d.n != len(cards) {
+		return false
+	}
+	for i, c := range cards {
+		if d.cards[(d.front+i)%len(d.cards)] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// Positions seen so far in a game, keyed by the hash of both decks, with the
+// cards kept so that two positions with the same hash are not mistaken for
+// a repeat. The cards of every position are stored one after another in a
+// single buffer, as the size of the first deck and then the cards of both.
+type Positions struct {
+	index map[uint64][]int // start of each position in cards, by hash
+	cards []int
+}
+
+func newPositions() *Positions {
+	return &Positions{index: map[uint64][]int{}}
+}
+
+// Forget all the positions, keeping the memory for reuse
+func (s *Positions) Reset() {
+	clear(s.index)
+	s.cards = s.cards[:0]
+}
+
+// Record the position of both decks, returning true if exactly the same
+// position was already seen
+func (s *Positions) Seen(d1, d2 *Deck) bool {
+	h := hashDecks(d1, d2)
+	n := d1.n + d2.n
+	for _, start := range s.index[h] {
+		p := s.cards[start+1 : start+1+n]
+		if s.cards[start] == d1.n && d1.Equal(p[:d1.n]) && d2.Equal(p[d1.n:]) {
+			return true
+		}
+	}
+	s.index[h] = append(s.index[h], len(s.cards))
+	s.cards = append(s.cards, d1.n)
+	s.cards = d1.appendTo(s.cards)
+	s.cards = d2.appendTo(s.cards)
+	return false
+}
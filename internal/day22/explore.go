@@ -0,0 +1,183 @@
+// Explore day 22 Recursive Combat beyond the puzzle input: deal random
+// shuffled decks, play many games, and report how long the games take, how
+// deep the sub-games go, and how often the rule that prevents infinite
+// games (a repeated position is won by player 1) decides a game
+
+package day22
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// Statistics for one game, including its sub-games
+type GameStats struct {
+	Winner      int  // winner of the main game
+	Rounds      int  // rounds in the main game
+	TotalRounds int  // rounds in the main game and all sub-games
+	SubGames    int  // number of sub-games
+	MaxDepth    int  // deepest sub-game, 0 if none
+	Repeats     int  // games and sub-games ended by a repeated position
+	MainRepeat  bool // main game ended by a repeated position
+}
+
+// Shuffle cards 1 to n, and deal the first half to player 1 and the rest to
+// player 2. There must be at least 2 cards, so both players have some.
+func dealDecks(rng *rand.Rand, n int) ([]int, []int, error) {
+	if n < 2 {
+		return nil, nil, fmt.Errorf("invalid number of cards %d, must be at least 2", n)
+	}
+	cards := rng.Perm(n)
+	for i := range cards {
+		cards[i]++
+	}
+	return cards[:n/2], cards[n/2:], nil
+}
+
+// Write decks in the same format as the puzzle input
+func writeDecks(filename string, player1, player2 []int) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	// Buffered writes keep the first error, so it only needs checking once
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "Player 1:")
+	for _, c := range player1 {
+		fmt.Fprintln(w, c)
+	}
+	fmt.Fprintln(w, "\nPlayer 2:")
+	for _, c := range player2 {
+		fmt.Fprintln(w, c)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Play a game of Recursive Combat, collecting statistics from its log
+func playStats(player1, player2 []int) GameStats {
+	var st GameStats
+	g := Game{Recursive: true, Log: func(e LogEntry) {
+		switch e.Event {
+		case "start":
+			if e.Depth > 0 {
+				st.SubGames++
+			}
+			st.MaxDepth = max(st.MaxDepth, e.Depth)
+		case "round":
+			st.TotalRounds++
+			if e.Depth == 0 {
+				st.Rounds++
+			}
+		case "end":
+			if e.Repeat {
+				st.Repeats++
+				st.MainRepeat = st.MainRepeat || e.Depth == 0
+			}
+		}
+	}}
+	st.Winner, _ = g.Play(player1, player2)
+	return st
+}
+
+// Play the given number of games with random decks of n cards, and report
+// the distribution of each statistic
+func exploreGames(games, n int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	stats := []GameStats{}
+	for i := 0; i < games; i++ {
+		player1, player2, err := dealDecks(rng, n)
+		if err != nil {
+			return err
+		}
+		stats = append(stats, playStats(player1, player2))
+	}
+	fmt.Printf("Played %d games of Recursive Combat with %d cards, seed %d\n\n", games, n, seed)
+
+	// Summary of each statistic
+	fmt.Printf("%-20s %8s %8s %8s %8s %8s %8s\n", "", "min", "median", "mean", "p90", "p99", "max")
+	for _, s := range []struct {
+		name string
+		get  func(GameStats) int
+	}{
+		{"rounds (main game)", func(st GameStats) int { return st.Rounds }},
+		{"rounds (total)", func(st GameStats) int { return st.TotalRounds }},
+		{"sub-games", func(st GameStats) int { return st.SubGames }},
+		{"max depth", func(st GameStats) int { return st.MaxDepth }},
+		{"repeat endings", func(st GameStats) int { return st.Repeats }},
+	} {
+		values := []int{}
+		for _, st := range stats {
+			values = append(values, s.get(st))
+		}
+		printDistribution(s.name, values)
+	}
+
+	// How often each player wins, and how often repeats decide games
+	var wins1, mainRepeats, anyRepeats int
+	depths := map[int]int{}
+	for _, st := range stats {
+		if st.Winner == 1 {
+			wins1++
+		}
+		if st.MainRepeat {
+			mainRepeats++
+		}
+		if st.Repeats > 0 {
+			anyRepeats++
+		}
+		depths[st.MaxDepth]++
+	}
+	fmt.Println()
+	fmt.Printf("Player 1 won %d games (%.1f%%)\n", wins1, percent(wins1, games))
+	fmt.Printf("Repeated position ended the main game in %d games (%.1f%%)\n",
+		mainRepeats, percent(mainRepeats, games))
+	fmt.Printf("Repeated position ended at least one game or sub-game in %d games (%.1f%%)\n",
+		anyRepeats, percent(anyRepeats, games))
+
+	// Histogram of maximum depth
+	fmt.Println("\nMaximum depth of sub-games:")
+	keys := []int{}
+	for d := range depths {
+		keys = append(keys, d)
+	}
+	sort.Ints(keys)
+	for _, d := range keys {
+		fmt.Printf("%4d: %6d games (%5.1f%%)\n", d, depths[d], percent(depths[d], games))
+	}
+	return nil
+}
+
+// Print minimum, median, mean, 90th and 99th percentiles, and maximum of
+// a list of values
+func printDistribution(name string, values []int) {
+	if len(values) == 0 {
+		return
+	}
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+	total := 0
+	for _, v := range sorted {
+		total += v
+	}
+	pct := func(p float64) int {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	fmt.Printf("%-20s %8d %8d %8.1f %8d %8d %8d\n", name, sorted[0], pct(0.5),
+		float64(total)/float64(len(sorted)), pct(0.9), pct(0.99), sorted[len(sorted)-1])
+}
+
+// Percentage, 0 if the total is 0
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
@@ -0,0 +1,281 @@
+// Structured log of day 22 games, one JSON object per line, and replaying
+// a log to check that every game and round follows the rules, so logs from
+// different implementations can be compared and checked
+
+package day22
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// One event in a game: "start" of a game or sub-game, a "round" played, or
+// the "end" of a game. Decks are as they are after the event.
+type LogEntry struct {
+	Event     string `json:"event"`
+	Game      int    `json:"game"`                // game number, 1 for the main game
+	Depth     int    `json:"depth"`               // 0 for the main game, 1 for its sub-games, etc.
+	Recursive bool   `json:"recursive,omitempty"` // start: game is Recursive Combat
+	Round     int    `json:"round,omitempty"`     // round: number within the game, from 1
+	Card1     int    `json:"card1,omitempty"`     // round: card drawn by player 1
+	Card2     int    `json:"card2,omitempty"`     // round: card drawn by player 2
+	SubGame   int    `json:"subgame,omitempty"`   // round: sub-game that decided it, if any
+	Winner    int    `json:"winner,omitempty"`    // round or end: player who won
+	Repeat    bool   `json:"repeat,omitempty"`    // end: won by player 1 on a repeated position
+	Deck1     []int  `json:"deck1"`
+	Deck2     []int  `json:"deck2"`
+}
+
+// Logger that writes each entry to w as a line of JSON
+func jsonLogger(w io.Writer) func(LogEntry) {
+	enc := json.NewEncoder(w)
+	return func(e LogEntry) {
+		enc.Encode(e)
+	}
+}
+
+// Combine two loggers into one, either of which may be nil
+func combineLoggers(a, b func(LogEntry)) func(LogEntry) {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return func(e LogEntry) {
+		a(e)
+		b(e)
+	}
+}
+
+// Logger that prints each event, indented by the depth of the game
+func printEntry(e LogEntry) {
+	indent := fmt.Sprintf("%*s", e.Depth*2, "")
+	switch e.Event {
+	case "start":
+		fmt.Printf("%s=== Game %d ===\n", indent, e.Game)
+	case "round":
+		fmt.Printf("%sRound %d (Game %d): player 1 plays %d, player 2 plays %d, player %d wins\n",
+			indent, e.Round, e.Game, e.Card1, e.Card2, e.Winner)
+		fmt.Printf("%s  Player 1: %v\n%s  Player 2: %v\n", indent, e.Deck1, indent, e.Deck2)
+	case "end":
+		fmt.Printf("%sThe winner of game %d is player %d\n", indent, e.Game, e.Winner)
+	}
+}
+
+// State of a game while replaying a log
+type replayState struct {
+	game, depth, round int
+	recursive          bool
+	deck1, deck2       []int
+	seen               map[string]bool
+	subGame, subWinner int // sub-game that has just ended, and its winner
+}
+
+// Replay a log file, returning the number of games and rounds in it
+func replayLog(filename string) (int, int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	return validateLog(f)
+}
+
+// Check every entry in a log against the rules, starting from the decks in
+// the first entry of each main game, returning the number of games and
+// rounds, or the first entry that breaks the rules
+func validateLog(r io.Reader) (int, int, error) {
+	var stack []*replayState // games in progress, the innermost last
+	var games, rounds int
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var e LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return games, rounds, fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := replayEntry(&stack, e); err != nil {
+			return games, rounds, fmt.Errorf("line %d: game %d: %v", line, e.Game, err)
+		}
+		if e.Event == "start" {
+			games++
+		} else if e.Event == "round" {
+			rounds++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return games, rounds, err
+	}
+	if len(stack) > 0 {
+		return games, rounds, fmt.Errorf("log ends during game %d", stack[len(stack)-1].game)
+	}
+	return games, rounds, nil
+}
+
+// Apply one log entry to the games in progress, checking that it follows
+// from the current state of the innermost game
+func replayEntry(stack *[]*replayState, e LogEntry) error {
+
+	// A new game: either a main game, or a sub-game which must follow from
+	// the cards at the top of the decks in the game in progress
+	var s *replayState
+	if len(*stack) > 0 {
+		s = (*stack)[len(*stack)-1]
+	}
+	if e.Event == "start" {
+		if err := checkCards(e.Deck1, e.Deck2); err != nil {
+			return err
+		}
+		ns := &replayState{game: e.Game, depth: e.Depth, recursive: e.Recursive,
+			deck1: copyDeck(e.Deck1), deck2: copyDeck(e.Deck2), seen: map[string]bool{}}
+		if s == nil {
+			if e.Depth != 0 {
+				return fmt.Errorf("main game has depth %d", e.Depth)
+			}
+		} else {
+			if !s.canRecurse() || s.subGame != 0 || s.seen[fmt.Sprint(s.deck1, s.deck2)] {
+				return fmt.Errorf("sub-game started, but game %d round %d does not need one",
+					s.game, s.round+1)
+			}
+			if e.Depth != s.depth+1 || e.Recursive != s.recursive {
+				return fmt.Errorf("sub-game depth or rules do not match game %d", s.game)
+			}
+			c1, c2 := s.deck1[0], s.deck2[0]
+			if !sameDeck(e.Deck1, s.deck1[1:c1+1]) || !sameDeck(e.Deck2, s.deck2[1:c2+1]) {
+				return fmt.Errorf("sub-game decks %v %v should be %v %v",
+					e.Deck1, e.Deck2, s.deck1[1:c1+1], s.deck2[1:c2+1])
+			}
+		}
+		*stack = append(*stack, ns)
+		return nil
+	}
+
+	// Otherwise must be part of the game in progress
+	if s == nil {
+		return fmt.Errorf("%s event outside a game", e.Event)
+	}
+	if e.Game != s.game {
+		return fmt.Errorf("%s event, but game %d is in progress", e.Event, s.game)
+	}
+	key := fmt.Sprint(s.deck1, s.deck2)
+	switch e.Event {
+
+	case "round":
+		if e.Round != s.round+1 {
+			return fmt.Errorf("round %d should be round %d", e.Round, s.round+1)
+		}
+		if len(s.deck1) == 0 || len(s.deck2) == 0 {
+			return fmt.Errorf("round %d played after game was over", e.Round)
+		}
+		if s.recursive && s.seen[key] {
+			return fmt.Errorf("round %d played from a repeated position", e.Round)
+		}
+		s.seen[key] = true
+		s.round++
+
+		// Check the cards drawn, and who wins
+		card1, card2 := s.deck1[0], s.deck2[0]
+		if e.Card1 != card1 || e.Card2 != card2 {
+			return fmt.Errorf("round %d cards %d,%d should be %d,%d",
+				e.Round, e.Card1, e.Card2, card1, card2)
+		}
+		winner, sub := 2, 0
+		if s.canRecurse() {
+			if s.subGame == 0 {
+				return fmt.Errorf("round %d needs a sub-game", e.Round)
+			}
+			winner, sub = s.subWinner, s.subGame
+		} else if card1 > card2 {
+			winner = 1
+		}
+		if e.Winner != winner || e.SubGame != sub {
+			return fmt.Errorf("round %d should be won by player %d (sub-game %d), not %d (sub-game %d)",
+				e.Round, winner, sub, e.Winner, e.SubGame)
+		}
+		s.subGame, s.subWinner = 0, 0
+
+		// Winner keeps both cards, and the decks should now match
+		s.deck1, s.deck2 = s.deck1[1:], s.deck2[1:]
+		if winner == 1 {
+			s.deck1 = append(s.deck1, card1, card2)
+		} else {
+			s.deck2 = append(s.deck2, card2, card1)
+		}
+		if !sameDeck(e.Deck1, s.deck1) || !sameDeck(e.Deck2, s.deck2) {
+			return fmt.Errorf("round %d decks %v %v should be %v %v",
+				e.Round, e.Deck1, e.Deck2, s.deck1, s.deck2)
+		}
+
+	case "end":
+		winner := 0
+		if e.Repeat {
+			if !s.recursive || !s.seen[key] {
+				return fmt.Errorf("game ended on a position that is not a repeat")
+			}
+			winner = 1
+		} else if len(s.deck2) == 0 {
+			winner = 1
+		} else if len(s.deck1) == 0 {
+			winner = 2
+		} else {
+			return fmt.Errorf("game ended with cards left for both players")
+		}
+		if e.Winner != winner {
+			return fmt.Errorf("game should be won by player %d, not %d", winner, e.Winner)
+		}
+		if !sameDeck(e.Deck1, s.deck1) || !sameDeck(e.Deck2, s.deck2) {
+			return fmt.Errorf("final decks %v %v should be %v %v",
+				e.Deck1, e.Deck2, s.deck1, s.deck2)
+		}
+
+		// Pass the result to the game that started this sub-game
+		*stack = (*stack)[:len(*stack)-1]
+		if len(*stack) > 0 {
+			parent := (*stack)[len(*stack)-1]
+			parent.subGame, parent.subWinner = e.Game, winner
+		}
+
+	default:
+		return fmt.Errorf("unknown event %q", e.Event)
+	}
+	return nil
+}
+
+// Does the next round of a game need a sub-game, i.e., is it Recursive
+// Combat and does each player have at least as many cards left as the card
+// they will draw?
+func (s *replayState) canRecurse() bool {
+	return s.recursive && len(s.deck1) > 0 && len(s.deck2) > 0 &&
+		s.deck1[0] > 0 && s.deck2[0] > 0 &&
+		len(s.deck1) > s.deck1[0] && len(s.deck2) > s.deck2[0]
+}
+
+// Check that every card in the decks is a positive number, since cards are
+// used as the sizes of the decks for sub-games
+func checkCards(decks ...[]int) error {
+	for _, d := range decks {
+		for _, c := range d {
+			if c < 1 {
+				return fmt.Errorf("invalid card %d, cards must be positive", c)
+			}
+		}
+	}
+	return nil
+}
+
+// Are two decks the same?
+func sameDeck(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,260 @@
+// Advent of Code 2020, Day 23
+//
+// A crab moves cups around a circle: each move, it picks up the three cups
+// after the current cup and puts them after the "destination" cup, the next
+// lower label. Part 1 reports the order of the cups after 100 moves, Part 2
+// extends the circle to a million cups and does ten million moves.
+//
+// The cups are a ring (circular list). Each cup holds the rank of its label
+// among all the labels, and there is a slice of the place of each rank in
+// the ring, so the destination cup is just the next lower rank, and doesn't
+// need a search. Labels can be any distinct numbers, however far apart,
+// given on the command line or in a file.
+
+package day23
+
+import (
+	"container/ring"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The circle of cups
+type Cups struct {
+	current *ring.Ring   // current cup, the value of each cup is the rank of its label
+	labels  []int        // labels of all the cups in increasing order, i.e., by rank
+	places  []*ring.Ring // place of each rank in the ring
+}
+
+func Main(args []string) {
+
+	// Command line options
+	fs := flag.NewFlagSet("day23", flag.ExitOnError)
+	labels := fs.String("cups", "157623984", "labels of the cups, as digits, or numbers separated by commas")
+	fname := fs.String("input", "", "read the cup labels from the first line of this file, instead of -cups")
+	moves := fs.Int("moves", 100, "number of moves for Part 1")
+	total := fs.Int("total", 1000000, "number of cups for Part 2")
+	moves2 := fs.Int("moves2", 10000000, "number of moves for Part 2")
+	trace := fs.Int("trace", 0, "print the first N moves of Part 1, in the same format as the puzzle")
+	fs.Parse(args)
+
+	// Get the labels from the command line, or a file
+	spec := *labels
+	if *fname != "" {
+		data, err := ioutil.ReadFile(*fname)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		spec = strings.SplitN(string(data), "\n", 2)[0]
+	}
+	start, err := parseLabels(spec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Part 1: order of the cups after cup 1
+	cups, err := newCups(start, 0)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	for i := 1; i <= *moves; i++ {
+		if i <= *trace {
+			fmt.Printf("-- move %d --\n", i)
+			fmt.Println("cups:", cups.Format(i-1))
+		}
+		picked, dest := cups.Move()
+		if i <= *trace {
+			fmt.Printf("pick up: %d, %d, %d\n", picked[0], picked[1], picked[2])
+			fmt.Printf("destination: %d\n\n", dest)
+		}
+	}
+	if *trace >= *moves {
+		fmt.Println("-- final --")
+		fmt.Printf("cups: %s\n\n", cups.Format(*moves))
+	}
+	after, err := cups.After(1, len(start)-1)
+	if err != nil {
+		fmt.Println("Part 1:", err)
+	} else {
+		fmt.Println("Part 1:", joinLabels(after))
+	}
+
+	// Part 2: product of the two cups after cup 1, with more cups and
+	// more moves
+	cups, err = newCups(start, *total)
+	if err != nil {
+		fmt.Println("Part 2:", err)
+		return
+	}
+	for i := 1; i <= *moves2; i++ {
+		cups.Move()
+	}
+	after, err = cups.After(1, 2)
+	if err != nil {
+		fmt.Println("Part 2:", err)
+		return
+	}
+	fmt.Printf("Part 2: %d x %d = %d\n", after[0], after[1], after[0]*after[1])
+}
+
+// Parse labels, either a string of digits (like the puzzle input), or
+// numbers separated by commas
+func parseLabels(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	var parts []string
+	if strings.Contains(s, ",") {
+		parts = strings.Split(s, ",")
+	} else {
+		parts = strings.Split(s, "")
+	}
+	labels := []int{}
+	for _, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cup label %q", p)
+		}
+		labels = append(labels, n)
+	}
+	return labels, nil
+}
+
+// Join labels into a string, with no separator (like the puzzle answer) if
+// they are all single digits, otherwise separated by commas
+func joinLabels(labels []int) string {
+	sep := ""
+	parts := []string{}
+	for _, n := range labels {
+		if n < 0 || n > 9 {
+			sep = ","
+		}
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return strings.Join(parts, sep)
+}
+
+// Create the circle of cups with the given labels, the first one being the
+// current cup. If total is more than the number of labels, the remaining
+// cups are numbered upwards from the highest label, until there are that
+// many cups.
+func newCups(labels []int, total int) (*Cups, error) {
+
+	// Need the current cup and three to pick up
+	n := max(len(labels), total)
+	if n < 4 {
+		return nil, fmt.Errorf("need at least 4 cups, not %d", n)
+	}
+
+	// Sort the labels to find the rank of each, and check for duplicates
+	c := &Cups{labels: append(make([]int, 0, n), labels...)}
+	sort.Ints(c.labels)
+	for i := 1; i < len(c.labels); i++ {
+		if c.labels[i] == c.labels[i-1] {
+			return nil, fmt.Errorf("duplicate cup label %d", c.labels[i])
+		}
+	}
+	for v := c.labels[len(c.labels)-1] + 1; len(c.labels) < n; v++ {
+		c.labels = append(c.labels, v)
+	}
+
+	// Create and populate the ring, in the order given, followed by the
+	// extra cups
+	c.current = ring.New(n)
+	c.places = make([]*ring.Ring, n)
+	r := c.current
+	for i := 0; i < n; i++ {
+		rank := i
+		if i < len(labels) {
+			rank = sort.SearchInts(c.labels, labels[i])
+		}
+		r.Value = rank
+		c.places[rank] = r
+		r = r.Next()
+	}
+	return c, nil
+}
+
+// Make one move, returning the labels of the cups picked up, and of the
+// destination cup
+func (c *Cups) Move() ([3]int, int) {
+
+	// 1. The crab picks up the three cups that are immediately clockwise
+	// of the current cup. They are removed from the circle; cup spacing is
+	// adjusted as necessary to maintain the circle.
+	removed := c.current.Unlink(3) // removes the 3 cups AFTER the current cup
+	var picked [3]int              // ranks of the cups picked up
+	for i := range picked {
+		picked[i] = removed.Value.(int)
+		removed = removed.Next()
+	}
+
+	// 2. The crab selects a destination cup: the cup with a label equal to
+	// the current cup's label minus one. If this would select one of the
+	// cups that was just picked up, the crab will keep subtracting one
+	// until it finds a cup that wasn't just picked up. If at any point in
+	// this process the value goes below the lowest value on any cup's
+	// label, it wraps around to the highest value on any cup's label
+	// instead. Labels don't have to be consecutive, so step down through
+	// the ranks instead, which skips labels that aren't cups.
+	dest := c.current.Value.(int)
+	for {
+		dest--
+		if dest < 0 {
+			dest = len(c.labels) - 1
+		}
+		if dest != picked[0] && dest != picked[1] && dest != picked[2] {
+			break
+		}
+	}
+
+	// 3. The crab places the cups it just picked up so that they are
+	// immediately clockwise of the destination cup. They keep the same
+	// order as when they were picked up.
+	c.places[dest].Link(removed)
+
+	// 4. The crab selects a new current cup: the cup which is immediately
+	// clockwise of the current cup.
+	c.current = c.current.Next()
+	for i, rank := range picked {
+		picked[i] = c.labels[rank]
+	}
+	return picked, c.labels[dest]
+}
+
+// Labels of the n cups clockwise of the cup with the given label
+func (c *Cups) After(label, n int) ([]int, error) {
+	rank := sort.SearchInts(c.labels, label)
+	if rank == len(c.labels) || c.labels[rank] != label {
+		return nil, fmt.Errorf("no cup labelled %d", label)
+	}
+	r := c.places[rank]
+	result := []int{}
+	for i := 0; i < n; i++ {
+		r = r.Next()
+		result = append(result, c.labels[r.Value.(int)])
+	}
+	return result, nil
+}
+
+// Format the circle like the puzzle description, with the current cup in
+// brackets, offset places from the start of the list (e.g., the number of
+// moves so far, so the cups don't appear to rotate)
+func (c *Cups) Format(offset int) string {
+	r := c.current.Move(-(offset % len(c.labels)))
+	var sb strings.Builder
+	for i := 0; i < len(c.labels); i++ {
+		if r == c.current {
+			fmt.Fprintf(&sb, "(%d)", c.labels[r.Value.(int)])
+		} else {
+			fmt.Fprintf(&sb, " %d ", c.labels[r.Value.(int)])
+		}
+		r = r.Next()
+	}
+	return sb.String()
+}
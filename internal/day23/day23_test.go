@@ -0,0 +1,117 @@
+// Unit tests and benchmarks for Day 23
+
+package day23
+
+import (
+	"testing"
+	"time"
+)
+
+// Play a number of moves from the given labels, and return the labels
+// after the cup with the given label
+func playMoves(t *testing.T, spec string, moves, label int) string {
+	labels, err := parseLabels(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cups, err := newCups(labels, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < moves; i++ {
+		cups.Move()
+	}
+	after, err := cups.After(label, len(labels)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return joinLabels(after)
+}
+
+// Test the example from the problem description
+func TestSample(t *testing.T) {
+	if s := playMoves(t, "389125467", 10, 1); s != "92658374" {
+		t.Error("After 10 moves should be 92658374, not", s)
+	}
+	if s := playMoves(t, "389125467", 100, 1); s != "67384529" {
+		t.Error("After 100 moves should be 67384529, not", s)
+	}
+}
+
+// Labels with gaps should give the same order as consecutive labels
+func TestArbitraryLabels(t *testing.T) {
+	s := playMoves(t, "30,80,90,10,20,50,40,60,70", 100, 10)
+	if s != "60,70,30,80,40,50,20,90" {
+		t.Error("After 100 moves should be 60,70,30,80,40,50,20,90, not", s)
+	}
+}
+
+// Labels far apart should not make the destination search step through
+// every label in between
+func TestSparseLabels(t *testing.T) {
+	done := make(chan string)
+	go func() {
+		done <- playMoves(t, "1,2,3,4,1000000000", 10, 1)
+	}()
+	select {
+	case s := <-done:
+		if s != "2,3,4,1000000000" {
+			t.Error("After 10 moves should be 2,3,4,1000000000, not", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("10 moves with sparse labels took more than a second")
+	}
+}
+
+// The trace should show the current cup in brackets, staying in place
+func TestFormat(t *testing.T) {
+	labels, _ := parseLabels("389125467")
+	cups, _ := newCups(labels, 0)
+	for i, want := range []string{
+		"(3) 8  9  1  2  5  4  6  7 ",
+		" 3 (2) 8  9  1  5  4  6  7 ",
+		" 3  2 (5) 4  6  7  8  9  1 ",
+	} {
+		if got := cups.Format(i); got != want {
+			t.Errorf("Move %d should be %q, not %q", i+1, want, got)
+		}
+		cups.Move()
+	}
+}
+
+// Too few or duplicate labels should be errors
+func TestInvalidCups(t *testing.T) {
+	for _, spec := range []string{"123", "12341", "1,2,x,4,5"} {
+		labels, err := parseLabels(spec)
+		if err == nil {
+			_, err = newCups(labels, 0)
+		}
+		if err == nil {
+			t.Error("Should be an error:", spec)
+		}
+	}
+}
+
+// Part 1 on the puzzle input
+func BenchmarkPart1(b *testing.B) {
+	labels, _ := parseLabels("157623984")
+	for i := 0; i < b.N; i++ {
+		cups, _ := newCups(labels, 0)
+		for j := 0; j < 100; j++ {
+			cups.Move()
+		}
+		cups.After(1, len(labels)-1)
+	}
+}
+
+// Part 2 on the puzzle input
+func BenchmarkPart2(b *testing.B) {
+	labels, _ := parseLabels("157623984")
+	for i := 0; i < b.N; i++ {
+		cups, _ := newCups(labels, 1000000)
+		for j := 0; j < 10000000; j++ {
+			cups.Move()
+		}
+		cups.After(1, 2)
+	}
+}
@@ -0,0 +1,374 @@
+// Advent of Code 2020, Day 24
+//
+// Given black/white tiles on a hexoganal grid, follow set of movement
+// directions and flip over tiles, then count number of black tiles.
+// For Part 2, simulate 100 days of flipping tiles based on state and
+// number of adjacent black tiles.
+//
+// Useful: https://www.redblobgames.com/grids/hexagons/
+//
+// AK, 5/09/2023
+
+package day24
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/andreaskaempf/adventofcode2020/internal/anim"
+	"github.com/andreaskaempf/adventofcode2020/internal/cycle"
+	"github.com/andreaskaempf/adventofcode2020/internal/parallel"
+)
+
+// A point is a coordinate in a hexagonal grid
+type Point struct {
+	x, y int
+}
+
+func Main(args []string) {
+
+	// Input file, and number of days to simulate (100 for the puzzle)
+	fs := flag.NewFlagSet("day24", flag.ExitOnError)
+	fname := fs.String("input", "input.txt", "input file")
+	maxDays := fs.Int("max", 100, "number of days to simulate")
+	svgPrefix := fs.String("svg", "", "write the floor after part 1 and after each day to <prefix>_dayNNN.svg")
+	ruleSpec := fs.String("rule", puzzleRule, "hex Life rule, B<neighbours to turn black>/S<neighbours to stay black>")
+	pattern := fs.String("pattern", "", "start from a pattern file (# for black tiles, drawn like -play) instead of the input")
+	workers := fs.Int("workers", 1, "number of goroutines to share the work of each day")
+	paths := fs.Bool("paths", false, "print the shortest equivalent path for each line, and its distance")
+	play := fs.Bool("play", false, "animate the floor in the terminal")
+	fps := fs.Float64("fps", 10, "frames per second when animating")
+	gifFile := fs.String("gif", "", "write the floor after each day to an animated GIF")
+	cell := fs.Int("cell", 3, "size of half a tile in the GIF, in pixels")
+	colours := fs.String("colours", "4080c0,f0f0f0,202020",
+		"GIF colours as RRGGBB: background, white tile, black tile")
+	fs.Parse(args)
+
+	// Check GIF options before doing any work
+	var gw *anim.GIFWriter
+	if *gifFile != "" {
+		var err error
+		gw, err = anim.NewGIFWriter(".#", *colours, *cell, 2, *fps)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+
+	// Parse the rule before doing any work
+	rule, err := parseHexRule(*ruleSpec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Do part 1: follow instructions to flip tiles, or start from a pattern
+	var coords map[Point]int
+	if *pattern != "" {
+		coords, err = readPattern(*pattern)
+	} else {
+		coords, err = flipTiles(*fname, *paths)
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Part 1: show number of black tiles (after any animation, if playing),
+	// and what the answers should be if this is the puzzle
+	puzzle := *pattern == "" && rule.String() == puzzleRule && *maxDays == 100
+	part1 := fmt.Sprintln("Part 1 (s/b 10 or 266):", sum(coords))
+	if *pattern != "" {
+		part1 = fmt.Sprintln("Starting pattern:", sum(coords), "black tiles")
+	}
+	var player *anim.Player
+	if *play {
+		player = anim.NewPlayer(*fps)
+		defer player.Close()
+	} else {
+		fmt.Print(part1)
+	}
+
+	// Animate and/or save each day if required, starting with the initial
+	// state
+	show := func(day int) bool {
+		rows, left, top := renderHex(coords)
+		if gw != nil {
+			gw.Add(rows, left, top)
+		}
+		if *svgPrefix != "" {
+			fname := fmt.Sprintf("%s_day%03d.svg", *svgPrefix, day)
+			title := fmt.Sprintf("Day %d: %d black tiles", day, sum(coords))
+			if err := writeHexSVG(fname, coords, title); err != nil {
+				fmt.Println("Error:", err)
+				*svgPrefix = ""
+			}
+		}
+		if player == nil {
+			return true
+		}
+		return player.Frame(fmt.Sprintf("Day %d: %d black tiles", day, sum(coords)), rows)
+	}
+	show(0)
+
+	// For part 2, simulate 100 days, or as many as required, using the
+	// rule. For the puzzle:
+	// 1. Any black tile with zero or more than 2 black tiles
+	//    immediately adjacent to it is flipped to white.
+	// 2. Any white tile with exactly 2 black tiles immediately adjacent
+	//    to it is flipped to black.
+	// The rules are applied simultaneously to every tile; put another
+	// way, it is first determined which tiles need to be flipped, then
+	// they are all flipped at the same time.
+	cycles := cycle.NewDetector()
+	cycles.Check(tileState(coords), 0)
+	days := *maxDays
+	for day := 1; day <= days; day++ {
+
+		// Apply the rule to every tile, all at once
+		coords = step(coords, rule, *workers)
+		if !show(day) {
+			break
+		}
+
+		// If this state has been seen before, the rest of the days just go
+		// round the same cycle, so only the last part of a lap needs to be
+		// simulated
+		if outcome, ok := cycles.Check(tileState(coords), day); ok {
+			if player == nil {
+				fmt.Println("Floor", outcome)
+			}
+			days = day + (days-day)%outcome.Period
+			cycles = cycle.NewDetector()
+		}
+	}
+
+	// Part 2: count up the black tiles
+	if player != nil {
+		player.Close()
+		fmt.Print(part1)
+	}
+	if puzzle {
+		fmt.Println("Part 2 (s/b 2208 or 3627):", sum(coords))
+	} else {
+		fmt.Printf("After %d days with %s: %d black tiles\n", *maxDays, rule, sum(coords))
+	}
+
+	// Write the GIF, if required
+	if gw != nil {
+		if err := gw.Save(*gifFile); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
+
+// Follow each line of directions in a file from the reference tile, and flip
+// the tile at the end, returning the colour of each tile flipped (1 for
+// black). Optionally prints the shortest path for each line.
+func flipTiles(filename string, paths bool) (map[Point]int, error) {
+
+	// Read input file
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to string and split lines
+	lines := strings.Split(string(data), "\n")
+
+	// Create an empty map of coordinates
+	coords := map[Point]int{} // empty map of coords
+	for i, line := range lines {
+
+		// Skip empty lines
+		if len(line) == 0 {
+			continue
+		}
+
+		// Start at 0,0 and move the point according to the instructions
+		insts, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		p := walk(Point{0, 0}, insts)
+		if paths {
+			fmt.Printf("%s -> %s (%d,%d), distance %d\n", line,
+				strings.Join(canonicalPath(insts), ""), p.x, p.y, distance(Point{0, 0}, p))
+		}
+
+		// Flip tile at this location
+		coords[p] = 1 - coords[p]
+	}
+	return coords, nil
+}
+
+// Draw the black tiles as #, with white tiles as ., covering the extent of
+// the black tiles. Each row of the hex grid is offset by half a tile from
+// the one above, so a tile at x,y is drawn in column 2x + y: east is two
+// columns to the right, north-east one column right on the row above, and
+// so on. Also returns the column and row of the top left.
+func renderHex(coords map[Point]int) ([][]byte, int, int) {
+
+	// Find extent of black tiles, in screen rows and columns
+	minC, maxC, minY, maxY := 0, 0, 0, 0
+	first := true
+	for p, c := range coords {
+		if c == 0 {
+			continue
+		}
+		col := 2*p.x + p.y
+		if first || col < minC {
+			minC = col
+		}
+		if first || col > maxC {
+			maxC = col
+		}
+		if first || p.y < minY {
+			minY = p.y
+		}
+		if first || p.y > maxY {
+			maxY = p.y
+		}
+		first = false
+	}
+
+	// Draw each row, with a tile in every other column
+	rows := [][]byte{}
+	for y := minY; y <= maxY; y++ {
+		row := []byte{}
+		for col := minC; col <= maxC; col++ {
+			if (col-y)%2 != 0 {
+				row = append(row, ' ')
+			} else if coords[Point{(col - y) / 2, y}] == 1 {
+				row = append(row, '#')
+			} else {
+				row = append(row, '.')
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, minC, minY
+}
+
+// Sum up the values of a map
+func sum(coords map[Point]int) int {
+	count := 0
+	for _, v := range coords {
+		count += v
+	}
+	return count
+}
+
+// Convert a line of instructions to a list of directions, e.g., "esenee"
+// is e, se, ne, e
+func parseLine(line string) ([]string, error) {
+	var directions []string
+	for i := 0; i < len(line); i++ {
+		if line[i] == 'e' || line[i] == 'w' {
+			directions = append(directions, line[i:i+1])
+		} else if (line[i] == 'n' || line[i] == 's') && i+1 < len(line) &&
+			(line[i+1] == 'e' || line[i+1] == 'w') {
+			directions = append(directions, line[i:i+2])
+			i++
+		} else {
+			return nil, fmt.Errorf("unknown direction at position %d: %q", i+1, line[i:min(i+2, len(line))])
+		}
+	}
+	return directions, nil
+}
+
+// Move a point in a hexagonal grid
+func move(p Point, dir string) Point {
+	switch dir {
+	case "e":
+		p.x++
+	case "w":
+		p.x--
+	case "ne":
+		p.x++
+		p.y--
+	case "nw":
+		p.y--
+	case "se":
+		p.y++
+	case "sw":
+		p.x--
+		p.y++
+	}
+	return p
+}
+
+// Apply the rule to every tile simultaneously, returning the black tiles
+// for the next day. Only black tiles and their neighbours can change, since
+// a white tile with no black neighbours stays white. The black tiles are
+// shared between the given number of goroutines (see internal/parallel).
+func step(coords map[Point]int, rule HexRule, workers int) map[Point]int {
+
+	// List the black tiles, so they can be divided between the workers. With
+	// more than one worker, sort them by row, so that each share is a band
+	// of rows, and only tiles at the edges of a band are next to tiles in
+	// another share.
+	workers = max(workers, 1)
+	black := make([]Point, 0, len(coords))
+	for p, c := range coords {
+		if c == 1 {
+			black = append(black, p)
+		}
+	}
+	if workers > 1 {
+		sort.Slice(black, func(i, j int) bool { return black[i].y < black[j].y })
+	}
+
+	// Accumulate changes based on state of tile and number of adjacent
+	// black tiles, for each worker's share of black tiles and their
+	// neighbours separately
+	changes := make([]map[Point]int, workers) // changes to be applied at end of day
+	parallel.Bands(len(black), workers, func(band, lo, hi int) {
+		changes[band] = map[Point]int{}
+		visit := func(n Point) {
+			if _, ok := changes[band][n]; !ok {
+				changes[band][n] = rule.Next(coords[n], countNeighbors(coords, n))
+			}
+		}
+		for _, p := range black[lo:hi] {
+			visit(p)
+			for _, n := range neighbours(p) {
+				visit(n)
+			}
+		}
+	})
+
+	// Apply changes at end of each day, keeping only the black tiles; a
+	// tile next to black tiles in two shares is worked out by both
+	// workers, with the same result
+	result := map[Point]int{}
+	for _, sc := range changes {
+		for p, c := range sc {
+			if c == 1 {
+				result[p] = 1
+			}
+		}
+	}
+	return result
+}
+
+// Count the number of neighbors of a point that are black
+func countNeighbors(coords map[Point]int, p Point) int {
+	count := 0
+	for _, n := range neighbours(p) {
+		count += coords[n]
+	}
+	return count
+}
+
+// The six tiles next to a point
+func neighbours(p Point) [6]Point {
+	var result [6]Point
+	for i, dir := range directions {
+		result[i] = move(p, dir)
+	}
+	return result
+}
@@ -0,0 +1,237 @@
+// Unit tests and benchmarks for Day 24
+
+package day24
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Valid lines should parse, anything else should be an error rather than
+// reading past the end of the line
+func TestParseLine(t *testing.T) {
+	dirs, err := parseLine("esenee")
+	if err != nil || strings.Join(dirs, ",") != "e,se,ne,e" {
+		t.Error("esenee should be e,se,ne,e, not", dirs, err)
+	}
+	for _, bad := range []string{"n", "es", "nwx", "enn", "eXw"} {
+		if _, err := parseLine(bad); err == nil {
+			t.Error("Should be an error:", bad)
+		}
+	}
+}
+
+// Examples from the problem description
+func TestWalk(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want Point
+	}{
+		{"esew", Point{0, 1}},
+		{"nwwswee", Point{0, 0}},
+	} {
+		dirs, _ := parseLine(tc.line)
+		if p := walk(Point{0, 0}, dirs); p != tc.want {
+			t.Error(tc.line, "should end at", tc.want, "not", p)
+		}
+	}
+}
+
+// Canonical paths of random paths should end on the same tile, be as long
+// as the distance to it, and be the same for any order of the same steps
+func TestCanonicalPath(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		dirs := []string{}
+		for j := rng.Intn(30); j > 0; j-- {
+			dirs = append(dirs, directions[rng.Intn(len(directions))])
+		}
+		c := canonicalPath(dirs)
+		p := walk(Point{0, 0}, dirs)
+		if walk(Point{0, 0}, c) != p {
+			t.Error("Canonical path", c, "does not end at the same tile as", dirs)
+		}
+		if len(c) != distance(Point{0, 0}, p) || len(c) > len(dirs) {
+			t.Error("Canonical path", c, "is not the shortest path for", dirs)
+		}
+		rng.Shuffle(len(dirs), func(a, b int) { dirs[a], dirs[b] = dirs[b], dirs[a] })
+		if strings.Join(canonicalPath(dirs), "") != strings.Join(c, "") {
+			t.Error("Canonical path depends on order of steps:", dirs)
+		}
+	}
+	if d := distance(Point{2, -3}, Point{-1, 1}); d != 4 {
+		t.Error("Distance should be 4, not", d)
+	}
+}
+
+// Neighbouring tiles in every direction should be drawn touching, i.e.,
+// their centres should be sqrt(3) sizes apart
+func TestHexCentre(t *testing.T) {
+	x0, y0 := hexCentre(Point{3, -2})
+	for _, d := range directions {
+		x, y := hexCentre(move(Point{3, -2}, d))
+		if dist := math.Hypot(x-x0, y-y0); math.Abs(dist-hexSize*math.Sqrt(3)) > 1e-9 {
+			t.Error("Tile to the", d, "is drawn", dist, "pixels away")
+		}
+	}
+	if x, y := hexCentre(Point{1, -1}); x <= 0 || y >= 0 {
+		t.Error("North-east should be up and to the right, not", x, y)
+	}
+}
+
+// The SVG should have one black hexagon for each black tile, all within the
+// viewBox, and an error should be returned if the file can't be created
+func TestWriteHexSVG(t *testing.T) {
+	coords := map[Point]int{{0, 0}: 1, {3, -2}: 1, {-4, 5}: 1, {1, 1}: 0, {-2, -3}: 1}
+	fname := t.TempDir() + "/floor.svg"
+	if err := writeHexSVG(fname, coords, "Test floor"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(fname)
+	svg := string(data)
+
+	// Size of the drawing
+	var width, height float64
+	m := regexp.MustCompile(`viewBox="0 0 ([0-9.]+) ([0-9.]+)"`).FindStringSubmatch(svg)
+	if m == nil {
+		t.Fatal("SVG should have a viewBox")
+	}
+	fmt.Sscan(m[1], &width)
+	fmt.Sscan(m[2], &height)
+
+	// Every corner of every hexagon should be inside it
+	black := 0
+	for _, poly := range regexp.MustCompile(`<polygon ([^>]*) points="([^"]*)"`).FindAllStringSubmatch(svg, -1) {
+		if strings.Contains(poly[1], `fill="#202020"`) {
+			black++
+		}
+		for _, pt := range strings.Fields(poly[2]) {
+			var x, y float64
+			if n, _ := fmt.Sscanf(pt, "%f,%f", &x, &y); n != 2 || x < 0 || y < 0 || x > width || y > height {
+				t.Errorf("Corner %s is outside the %gx%g viewBox", pt, width, height)
+			}
+		}
+	}
+	if black != sum(coords) {
+		t.Error("Should be", sum(coords), "black hexagons, not", black)
+	}
+	if !strings.Contains(svg, ">Test floor</text>") {
+		t.Error("SVG should have the title")
+	}
+
+	if err := writeHexSVG(t.TempDir()+"/missing/floor.svg", coords, ""); err == nil {
+		t.Error("SVG in a missing directory should be an error")
+	}
+}
+
+// Rules should parse in either order and case, and print in the standard
+// form, and invalid rules should be errors
+func TestParseHexRule(t *testing.T) {
+	for spec, want := range map[string]string{
+		"B2/S12": "B2/S12",
+		"s21/b2": "B2/S12",
+		"B/S":    "B/S",
+		"B36/S0": "B36/S0",
+	} {
+		r, err := parseHexRule(spec)
+		if err != nil || r.String() != want {
+			t.Error(spec, "should be", want, "not", r, err)
+		}
+	}
+	for _, bad := range []string{"", "B2", "B2/B3", "B7/S1", "X2/S1", "B02/S1"} {
+		if _, err := parseHexRule(bad); err == nil {
+			t.Error("Should be an error:", bad)
+		}
+	}
+	r, _ := parseHexRule(puzzleRule)
+	if r.Next(1, 0) != 0 || r.Next(1, 2) != 1 || r.Next(0, 2) != 1 || r.Next(0, 1) != 0 {
+		t.Error("Puzzle rule is wrong")
+	}
+}
+
+// Patterns are drawn with tiles in every other column, rows offset
+func TestReadPattern(t *testing.T) {
+	fname := t.TempDir() + "/pattern.txt"
+	for _, tc := range []struct {
+		pattern string
+		want    []Point
+	}{
+		{"#.#\n.#.\n", []Point{{0, 0}, {1, 0}, {0, 1}}},
+		{" # #\n# #\n", []Point{{0, 0}, {1, 0}, {-1, 1}, {0, 1}}},
+	} {
+		os.WriteFile(fname, []byte(tc.pattern), 0644)
+		coords, err := readPattern(fname)
+		if err != nil || len(coords) != len(tc.want) {
+			t.Error("Pattern", tc.pattern, "should be", tc.want, "not", coords, err)
+		}
+		for _, p := range tc.want {
+			if coords[p] != 1 {
+				t.Error("Pattern", tc.pattern, "should have", p, "black")
+			}
+		}
+	}
+	os.WriteFile(fname, []byte("##\n"), 0644)
+	if _, err := readPattern(fname); err == nil {
+		t.Error("Tiles in adjacent columns should be an error")
+	}
+}
+
+// Sharing the work between goroutines should give exactly the same floor as
+// one goroutine, for the puzzle rule and another. Run with go test -race to
+// check the workers don't share anything they shouldn't.
+func TestWorkersMatchSequential(t *testing.T) {
+	start, err := flipTiles("input.txt", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, spec := range []string{puzzleRule, "B24/S13"} {
+		rule, _ := parseHexRule(spec)
+		results := []string{}
+		for _, workers := range []int{1, 2, 5, 16} {
+			coords := start
+			for day := 0; day < 20; day++ {
+				coords = step(coords, rule, workers)
+			}
+			results = append(results, string(tileState(coords)))
+			if results[len(results)-1] != results[0] {
+				t.Error("Workers", workers, "give a different floor with", spec)
+			}
+		}
+	}
+}
+
+// Part 1 on the puzzle input, including reading the file
+func BenchmarkPart1(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		flipTiles("input.txt", false)
+	}
+}
+
+// Part 2 on the puzzle input
+func BenchmarkPart2(b *testing.B) {
+	start, _ := flipTiles("input.txt", false)
+	rule, _ := parseHexRule(puzzleRule)
+	for i := 0; i < b.N; i++ {
+		coords := start
+		for day := 0; day < 100; day++ {
+			coords = step(coords, rule, 1)
+		}
+	}
+}
+
+// Part 2 on the puzzle input, with 4 workers
+func BenchmarkPart2Workers(b *testing.B) {
+	start, _ := flipTiles("input.txt", false)
+	rule, _ := parseHexRule(puzzleRule)
+	for i := 0; i < b.N; i++ {
+		coords := start
+		for day := 0; day < 100; day++ {
+			coords = step(coords, rule, 4)
+		}
+	}
+}
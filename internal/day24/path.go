@@ -0,0 +1,64 @@
+// Paths on the day 24 hexagonal grid: following a list of directions,
+// reducing it to the shortest path to the same tile, and the distance
+// between tiles. Points are axial coordinates (see move()), so the third
+// cube coordinate is -x-y.
+
+package day24
+
+// Directions in the order they appear in a canonical path, clockwise from
+// east
+var directions = []string{"e", "se", "sw", "w", "nw", "ne"}
+
+// Follow a list of directions from a point
+func walk(p Point, dirs []string) Point {
+	for _, d := range dirs {
+		p = move(p, d)
+	}
+	return p
+}
+
+// Number of steps between two tiles, i.e., the length of the shortest path
+func distance(a, b Point) int {
+	dx, dy := b.x-a.x, b.y-a.y
+	return (abs(dx) + abs(dy) + abs(dx+dy)) / 2
+}
+
+// Shortest list of directions that ends on the same tile as the given one,
+// in a fixed order (see directions above), so equivalent paths always give
+// the same result
+func canonicalPath(dirs []string) []string {
+
+	// Count the steps needed in each direction to reach the end point: at
+	// most two directions, which are next to each other
+	p := walk(Point{0, 0}, dirs)
+	count := map[string]int{}
+	switch {
+	case p.x >= 0 && p.y >= 0:
+		count["e"], count["se"] = p.x, p.y
+	case p.x <= 0 && p.y <= 0:
+		count["w"], count["nw"] = -p.x, -p.y
+	case p.x > 0: // and y < 0
+		k := min(p.x, -p.y)
+		count["ne"], count["e"], count["nw"] = k, p.x-k, -p.y-k
+	default: // x < 0, y > 0
+		k := min(-p.x, p.y)
+		count["sw"], count["w"], count["se"] = k, -p.x-k, p.y-k
+	}
+
+	// List the steps in order
+	result := []string{}
+	for _, d := range directions {
+		for i := 0; i < count[d]; i++ {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Absolute value of a number
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
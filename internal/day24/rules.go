@@ -0,0 +1,111 @@
+// Hex Life rules for day 24, written like Life rules as B<births>/S<survivals>,
+// e.g., B2/S12 for the puzzle: a white tile with exactly 2 black neighbours
+// turns black, a black tile with 1 or 2 black neighbours stays black, and
+// every other tile is white. Also reads starting patterns drawn the same way
+// as the floor is shown.
+
+package day24
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Rule for the puzzle
+const puzzleRule = "B2/S12"
+
+// Which numbers of black neighbours (0 to 6) turn a white tile black, and
+// keep a black tile black
+type HexRule struct {
+	Birth   [7]bool
+	Survive [7]bool
+}
+
+// Parse a rule like B2/S12, in either order and either case
+func parseHexRule(spec string) (HexRule, error) {
+	var r HexRule
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(spec)), "/")
+	if len(parts) != 2 {
+		return r, fmt.Errorf("invalid rule %q, should be like %s", spec, puzzleRule)
+	}
+	seen := map[byte]bool{}
+	for _, p := range parts {
+		if len(p) == 0 || (p[0] != 'B' && p[0] != 'S') || seen[p[0]] {
+			return r, fmt.Errorf("invalid rule %q, should be like %s", spec, puzzleRule)
+		}
+		seen[p[0]] = true
+		counts := &r.Birth
+		if p[0] == 'S' {
+			counts = &r.Survive
+		}
+		for _, c := range p[1:] {
+			if c < '0' || c > '6' {
+				return r, fmt.Errorf("invalid number of neighbours %q in rule %q", c, spec)
+			}
+			counts[c-'0'] = true
+		}
+	}
+
+	// A white tile with no black neighbours turning black would make the
+	// whole infinite floor black
+	if r.Birth[0] {
+		return r, fmt.Errorf("rule %q is not supported, B0 turns the whole floor black", spec)
+	}
+	return r, nil
+}
+
+// Rule in the form B.../S...
+func (r HexRule) String() string {
+	var sb strings.Builder
+	sb.WriteByte('B')
+	for n, b := range r.Birth {
+		if b {
+			fmt.Fprint(&sb, n)
+		}
+	}
+	sb.WriteString("/S")
+	for n, s := range r.Survive {
+		if s {
+			fmt.Fprint(&sb, n)
+		}
+	}
+	return sb.String()
+}
+
+// Colour of a tile on the next day (1 for black), given its colour now and
+// its number of black neighbours
+func (r HexRule) Next(colour, nblack int) int {
+	if (colour == 1 && r.Survive[nblack]) || (colour == 0 && r.Birth[nblack]) {
+		return 1
+	}
+	return 0
+}
+
+// Read a starting pattern, drawn the same way as renderHex(): # for a black
+// tile, any other character for white, with tiles in every other column and
+// each row offset by one column from the one above. The first row is y = 0,
+// and the tile in the first or second column (whichever is on the grid) is
+// x = 0.
+func readPattern(filename string) (map[Point]int, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	coords := map[Point]int{}
+	parity := -1 // whether tiles are in even (0) or odd (1) columns of the first row
+	for y, line := range strings.Split(string(data), "\n") {
+		for col := 0; col < len(line); col++ {
+			if line[col] != '#' {
+				continue
+			}
+			if parity < 0 {
+				parity = (col + y) % 2
+			} else if (col+y)%2 != parity {
+				return nil, fmt.Errorf("line %d column %d: tile is not on the hex grid", y+1, col+1)
+			}
+			coords[Point{(col - y - parity) / 2, y}] = 1
+		}
+	}
+	return coords, nil
+}
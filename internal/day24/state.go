@@ -0,0 +1,33 @@
+// Encoding of the set of black tiles after each day of day 24, so a state
+// that repeats an earlier one can be recognised (see internal/cycle)
+
+package day24
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// The set of black tiles, as their coordinates in sorted order so that the
+// state does not depend on the order of the map
+func tileState(coords map[Point]int) []byte {
+	black := []Point{}
+	for p, c := range coords {
+		if c == 1 {
+			black = append(black, p)
+		}
+	}
+	sort.Slice(black, func(i, j int) bool {
+		if black[i].x != black[j].x {
+			return black[i].x < black[j].x
+		}
+		return black[i].y < black[j].y
+	})
+	buf := []byte{}
+	for _, p := range black {
+		for _, n := range []int{p.x, p.y} {
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(n))
+		}
+	}
+	return buf
+}
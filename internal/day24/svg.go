@@ -0,0 +1,103 @@
+// SVG export for day 24: the floor drawn as pointy-top hexagons, positioned
+// from the axial coordinates of each tile, with the reference tile outlined
+// and its six neighbours labelled with the direction that reaches them, to
+// check the mapping in move()
+
+package day24
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Size of each hexagon (centre to corner), and margin around the floor, in
+// pixels
+const (
+	hexSize   = 10
+	hexMargin = 20
+)
+
+// Centre of the hexagon for a tile, in pixels before the margin. For
+// pointy-top hexagons, east is sqrt(3) sizes to the right, and each row
+// is 1.5 sizes below the one to the north, offset by half a tile.
+func hexCentre(p Point) (float64, float64) {
+	return hexSize * math.Sqrt(3) * (float64(p.x) + float64(p.y)/2),
+		hexSize * 1.5 * float64(p.y)
+}
+
+// Write the floor to an SVG file: black tiles, and white tiles covering
+// the extent of the black tiles plus one tile all round, with a title
+func writeHexSVG(filename string, coords map[Point]int, title string) error {
+
+	// Find extent of black tiles and the reference tile, in the same rows
+	// and columns as renderHex()
+	minC, maxC, minY, maxY := 0, 0, 0, 0
+	for p, c := range coords {
+		if c == 1 {
+			minC, maxC = min(minC, 2*p.x+p.y), max(maxC, 2*p.x+p.y)
+			minY, maxY = min(minY, p.y), max(maxY, p.y)
+		}
+	}
+	minC, maxC, minY, maxY = minC-2, maxC+2, minY-1, maxY+1
+
+	// Offset from tile centres to pixels, allowing room for the title
+	left := float64(minC)*hexSize*math.Sqrt(3)/2 - hexMargin - hexSize
+	top := float64(minY)*hexSize*1.5 - 2*hexMargin - hexSize
+	width := float64(maxC-minC)*hexSize*math.Sqrt(3)/2 + 2*hexMargin + 2*hexSize
+	height := float64(maxY-minY)*hexSize*1.5 + 3*hexMargin + 2*hexSize
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\">\n",
+		width, height, width, height)
+	fmt.Fprintln(w, "<rect width=\"100%\" height=\"100%\" fill=\"#4080c0\"/>")
+	fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" font-size=\"14\" fill=\"white\">%s</text>\n",
+		hexMargin, hexMargin, title)
+
+	// Draw a hexagon for each tile, with corners every 60 degrees starting
+	// at -30 degrees, so there is a corner at the top
+	hexagon := func(p Point, style string) {
+		cx, cy := hexCentre(p)
+		fmt.Fprint(w, "<polygon ", style, " points=\"")
+		for i := 0; i < 6; i++ {
+			a := math.Pi / 180 * float64(60*i-30)
+			fmt.Fprintf(w, "%.1f,%.1f ", cx-left+hexSize*math.Cos(a), cy-top+hexSize*math.Sin(a))
+		}
+		fmt.Fprintln(w, "\"/>")
+	}
+	for y := minY; y <= maxY; y++ {
+		for col := minC; col <= maxC; col++ {
+			if (col-y)%2 != 0 {
+				continue
+			}
+			p := Point{(col - y) / 2, y}
+			if coords[p] == 1 {
+				hexagon(p, "fill=\"#202020\" stroke=\"#808080\" stroke-width=\"0.5\"")
+			} else {
+				hexagon(p, "fill=\"#f0f0f0\" stroke=\"#808080\" stroke-width=\"0.5\"")
+			}
+		}
+	}
+
+	// Outline the reference tile, and label its neighbours with the
+	// direction from it
+	hexagon(Point{0, 0}, "fill=\"none\" stroke=\"red\" stroke-width=\"2\"")
+	for _, d := range directions {
+		cx, cy := hexCentre(move(Point{0, 0}, d))
+		fmt.Fprintf(w, "<text x=\"%.1f\" y=\"%.1f\" font-size=\"7\" fill=\"red\" text-anchor=\"middle\">%s</text>\n",
+			cx-left, cy-top+2.5, d)
+	}
+
+	fmt.Fprintln(w, "</svg>")
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,30 @@
+// Package parallel shares the work of each generation of the simulations of
+// days 11, 17 and 24 between worker goroutines: the items (rows, seats, x
+// coordinates or tiles) are split into contiguous bands, one for each
+// worker, and all the workers finish before the next generation starts.
+// Workers only read the current state, and collect their own part of the
+// next state, so the result is the same for any number of workers.
+package parallel
+
+import "sync"
+
+// Split items 0 to n-1 into contiguous bands, at most one for each worker,
+// and call f with the number of each band and its start and end (exclusive),
+// each in its own goroutine (or directly, if there is only one worker).
+// Waits for all the bands to finish.
+func Bands(n, workers int, f func(band, lo, hi int)) {
+	if workers <= 1 || n <= 1 {
+		f(0, 0, n)
+		return
+	}
+	workers = min(workers, n)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			f(w, n*w/workers, n*(w+1)/workers)
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,27 @@
+// Unit tests for the parallel package
+
+package parallel
+
+import "testing"
+
+// Every item should be in exactly one band, for any number of workers
+func TestBands(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 100} {
+		for workers := 0; workers <= 9; workers++ {
+			count := make([]int, n)
+			Bands(n, workers, func(band, lo, hi int) {
+				if band < 0 || band >= max(workers, 1) {
+					t.Errorf("n = %d, workers = %d: band %d out of range", n, workers, band)
+				}
+				for i := lo; i < hi; i++ {
+					count[i]++ // bands don't overlap, so no race
+				}
+			})
+			for i, c := range count {
+				if c != 1 {
+					t.Errorf("n = %d, workers = %d: item %d in %d bands", n, workers, i, c)
+				}
+			}
+		}
+	}
+}
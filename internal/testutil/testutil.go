@@ -0,0 +1,39 @@
+// Package testutil has helpers shared by the tests and benchmarks of the
+// days.
+package testutil
+
+import (
+	"io"
+	"os"
+)
+
+// Call a function with standard output discarded, for benchmarking code
+// that prints its results
+func Quiet(f func()) {
+	stdout := os.Stdout
+	os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	defer func() {
+		os.Stdout.Close()
+		os.Stdout = stdout
+	}()
+	f()
+}
+
+// Call a function and return what it writes to standard output, for
+// testing code that prints its results
+func Capture(f func()) string {
+	stdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+	defer func() {
+		os.Stdout = stdout
+	}()
+	f()
+	w.Close()
+	return <-out
+}
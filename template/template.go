@@ -2,7 +2,7 @@
 //
 // AK, x/x/2022
 
-package main
+package dayXX
 
 import (
 	"fmt"
@@ -11,7 +11,7 @@ import (
 	"strings"
 )
 
-func main() {
+func Main(args []string) {
 	fmt.Println("vim-go")
 }
 